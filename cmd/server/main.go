@@ -1,31 +1,43 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"github.com/kavshevnova/product-reservation-system/pkg/app"
 	"github.com/kavshevnova/product-reservation-system/pkg/config"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func main() {
 	cfg := config.MustLoad()
-	fmt.Println(cfg)
 
 	logger := SetUpLogger(cfg.Env)
+	//Секреты в логе скрывает config.Config.LogValue
 	logger.Info("Стартуем", slog.Any("Config", cfg))
-	application := app.New(logger, cfg.GRPC.Port, cfg.StoragePath)
+	application := app.New(logger, cfg.GRPC.Port, cfg.HTTP.Port, cfg.StoragePath, cfg.Redis, cfg.Auth, cfg.Reservation, cfg.Payment, cfg.Outbox, cfg.Idempotency)
 	go func() {
 		application.GRPCsrv.MustStart()
 		logger.Info("starting gRPC server")
 	}()
+	go application.HTTPsrv.MustStart()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	go application.Expirer.Run(workersCtx)
+	go application.Relay.Run(workersCtx)
+	go application.Cleaner.Run(workersCtx)
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 	logger.Info("starting graceful shutdown")
+	stopWorkers()
 	application.GRPCsrv.Stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	application.HTTPsrv.Stop(shutdownCtx)
 	logger.Info("graceful shutdown complete")
 }
 
@@ -4,12 +4,13 @@
 // 	protoc        v5.29.3
 // source: shop/shop.proto
 
-package shop
+package shopv1
 
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,9 +24,17 @@ const (
 )
 
 type ListProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Limit int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // не больше 100
+	// offset оставлен для старых клиентов, вместе с page_token использовать нельзя
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// next_page_token из предыдущего ответа
+	PageToken         string         `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	IncludeTotalCount bool           `protobuf:"varint,4,opt,name=include_total_count,json=includeTotalCount,proto3" json:"include_total_count,omitempty"`
+	Filter            *ProductFilter `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	// price_asc, price_desc, name_asc, name_desc, newest; по умолчанию по product_id.
+	// page_token действует только с той же сортировкой
+	Sort          string `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,27 +83,63 @@ func (x *ListProductsRequest) GetOffset() int32 {
 	return 0
 }
 
-type ListProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetIncludeTotalCount() bool {
+	if x != nil {
+		return x.IncludeTotalCount
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetFilter() *ProductFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ProductFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	MinPrice *float32 `protobuf:"fixed32,1,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"` // используйте min_price_money
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	MaxPrice      *float32 `protobuf:"fixed32,2,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"` // используйте max_price_money
+	InStockOnly   bool     `protobuf:"varint,3,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	NameContains  string   `protobuf:"bytes,4,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"` // без учета регистра
+	CategoryId    int64    `protobuf:"varint,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`      // вместе с подкатегориями
+	MinPriceMoney *Money   `protobuf:"bytes,6,opt,name=min_price_money,json=minPriceMoney,proto3" json:"min_price_money,omitempty"`
+	MaxPriceMoney *Money   `protobuf:"bytes,7,opt,name=max_price_money,json=maxPriceMoney,proto3" json:"max_price_money,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProductsResponse) Reset() {
-	*x = ListProductsResponse{}
+func (x *ProductFilter) Reset() {
+	*x = ProductFilter{}
 	mi := &file_shop_shop_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductsResponse) String() string {
+func (x *ProductFilter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductsResponse) ProtoMessage() {}
+func (*ProductFilter) ProtoMessage() {}
 
-func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+func (x *ProductFilter) ProtoReflect() protoreflect.Message {
 	mi := &file_shop_shop_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -106,39 +151,86 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
-func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProductFilter.ProtoReflect.Descriptor instead.
+func (*ProductFilter) Descriptor() ([]byte, []int) {
 	return file_shop_shop_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *ListProductsResponse) GetProducts() []*Product {
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *ProductFilter) GetMinPrice() float32 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *ProductFilter) GetMaxPrice() float32 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetInStockOnly() bool {
 	if x != nil {
-		return x.Products
+		return x.InStockOnly
+	}
+	return false
+}
+
+func (x *ProductFilter) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *ProductFilter) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetMinPriceMoney() *Money {
+	if x != nil {
+		return x.MinPriceMoney
 	}
 	return nil
 }
 
-type GetProductInfoRequest struct {
+func (x *ProductFilter) GetMaxPriceMoney() *Money {
+	if x != nil {
+		return x.MaxPriceMoney
+	}
+	return nil
+}
+
+type Category struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CategoryId    int64                  `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	ParentId      int64                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 0 у корневых категорий
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductInfoRequest) Reset() {
-	*x = GetProductInfoRequest{}
+func (x *Category) Reset() {
+	*x = Category{}
 	mi := &file_shop_shop_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductInfoRequest) String() string {
+func (x *Category) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductInfoRequest) ProtoMessage() {}
+func (*Category) ProtoMessage() {}
 
-func (x *GetProductInfoRequest) ProtoReflect() protoreflect.Message {
+func (x *Category) ProtoReflect() protoreflect.Message {
 	mi := &file_shop_shop_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -150,43 +242,2138 @@ func (x *GetProductInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetProductInfoRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
 	return file_shop_shop_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetProductInfoRequest) GetProductId() int64 {
+func (x *Category) GetCategoryId() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.CategoryId
 	}
 	return 0
 }
 
-type GetProductInfoResponse struct {
+func (x *Category) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type CategoryNode struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Price         float32                `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Children      []*CategoryNode        `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductInfoResponse) Reset() {
-	*x = GetProductInfoResponse{}
+func (x *CategoryNode) Reset() {
+	*x = CategoryNode{}
 	mi := &file_shop_shop_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductInfoResponse) String() string {
+func (x *CategoryNode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductInfoResponse) ProtoMessage() {}
+func (*CategoryNode) ProtoMessage() {}
+
+func (x *CategoryNode) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryNode.ProtoReflect.Descriptor instead.
+func (*CategoryNode) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CategoryNode) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *CategoryNode) GetChildren() []*CategoryNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type ListCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ParentId      int64                  `protobuf:"varint,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // прямые подкатегории; 0 - корневые категории
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`                           // все категории, parent_id не учитывается
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_shop_shop_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCategoriesRequest) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *ListCategoriesRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type ListCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_shop_shop_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesResponse) ProtoMessage() {}
+
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type GetCategoryTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootId        int64                  `protobuf:"varint,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"` // 0 - весь каталог
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryTreeRequest) Reset() {
+	*x = GetCategoryTreeRequest{}
+	mi := &file_shop_shop_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryTreeRequest) ProtoMessage() {}
+
+func (x *GetCategoryTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryTreeRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetCategoryTreeRequest) GetRootId() int64 {
+	if x != nil {
+		return x.RootId
+	}
+	return 0
+}
+
+type GetCategoryTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roots         []*CategoryNode        `protobuf:"bytes,1,rep,name=roots,proto3" json:"roots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryTreeResponse) Reset() {
+	*x = GetCategoryTreeResponse{}
+	mi := &file_shop_shop_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryTreeResponse) ProtoMessage() {}
+
+func (x *GetCategoryTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoryTreeResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetCategoryTreeResponse) GetRoots() []*CategoryNode {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+type ListProductsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// Пустой на последней странице
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Заполняется, только если include_total_count = true
+	TotalCount    int64 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_shop_shop_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListProductsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type SearchProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // по умолчанию 20, не больше 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_shop_shop_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchProductsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// По убыванию релевантности
+	Hits []*ProductSearchHit `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	// По словам ничего не нашлось, результаты подобраны по похожести названия
+	Fuzzy         bool `protobuf:"varint,2,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_shop_shop_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchProductsResponse) GetHits() []*ProductSearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+type ProductSearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Rank          float32                `protobuf:"fixed32,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	Snippet       string                 `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"` // название, найденные слова в <b></b>
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchHit) Reset() {
+	*x = ProductSearchHit{}
+	mi := &file_shop_shop_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchHit) ProtoMessage() {}
+
+func (x *ProductSearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchHit.ProtoReflect.Descriptor instead.
+func (*ProductSearchHit) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ProductSearchHit) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchHit) GetRank() float32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *ProductSearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type GetProductInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductInfoRequest) Reset() {
+	*x = GetProductInfoRequest{}
+	mi := &file_shop_shop_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductInfoRequest) ProtoMessage() {}
+
+func (x *GetProductInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetProductInfoRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetProductInfoRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type GetProductInfoResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Price float32 `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"` // используйте price_money
+	Stock int32   `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`  // у товара с вариантами - сумма остатков вариантов
+	// Если варианты есть, заказывать нужно конкретный вариант
+	Variants      []*ProductVariant `protobuf:"bytes,5,rep,name=variants,proto3" json:"variants,omitempty"`
+	PriceMoney    *Money            `protobuf:"bytes,6,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductInfoResponse) Reset() {
+	*x = GetProductInfoResponse{}
+	mi := &file_shop_shop_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductInfoResponse) ProtoMessage() {}
+
+func (x *GetProductInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetProductInfoResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetProductInfoResponse) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *GetProductInfoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *GetProductInfoResponse) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *GetProductInfoResponse) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *GetProductInfoResponse) GetVariants() []*ProductVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *GetProductInfoResponse) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+type ProductVariant struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	VariantId  int64                  `protobuf:"varint,1,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku        string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Attributes map[string]string      `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // например size, color
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Price         float32 `protobuf:"fixed32,4,opt,name=price,proto3" json:"price,omitempty"` // используйте price_money
+	Stock         int32   `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	PriceMoney    *Money  `protobuf:"bytes,6,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"` // своя цена варианта или цена товара
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariant) Reset() {
+	*x = ProductVariant{}
+	mi := &file_shop_shop_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariant) ProtoMessage() {}
+
+func (x *ProductVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariant.ProtoReflect.Descriptor instead.
+func (*ProductVariant) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProductVariant) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *ProductVariant) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+type Product struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Price         float32 `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"` // используйте price_money
+	Stock         int32   `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	PriceMoney    *Money  `protobuf:"bytes,5,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_shop_shop_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Product) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *Product) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Product) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+// Денежная сумма без потери точности
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`    // в минимальных единицах валюты: 199999 = 1999.99
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217, например USD
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_shop_shop_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type MakeOrderRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // необязателен, берется из токена; другой id -> PermissionDenied
+	// product_id и quantity - заказ из одного товара, для старых клиентов.
+	// Для нескольких товаров используется items
+	ProductId int64        `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32        `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Items     []*OrderItem `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	// Повтор запроса с тем же ключом вернет исходный ответ, а не создаст второй заказ.
+	// Ключ можно передать и в метаданных idempotency-key
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MakeOrderRequest) Reset() {
+	*x = MakeOrderRequest{}
+	mi := &file_shop_shop_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakeOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeOrderRequest) ProtoMessage() {}
+
+func (x *MakeOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeOrderRequest.ProtoReflect.Descriptor instead.
+func (*MakeOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MakeOrderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MakeOrderRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *MakeOrderRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *MakeOrderRequest) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *MakeOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type MakeOrderResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	OrderId    int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status     string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // reserved: заказ ждет оплаты
+	PaymentURL string                 `protobuf:"bytes,3,opt,name=paymentURL,proto3" json:"paymentURL,omitempty"`
+	Items      []*OrderItem           `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Sum           float32 `protobuf:"fixed32,5,opt,name=sum,proto3" json:"sum,omitempty"` // используйте sum_money
+	SumMoney      *Money  `protobuf:"bytes,6,opt,name=sum_money,json=sumMoney,proto3" json:"sum_money,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakeOrderResponse) Reset() {
+	*x = MakeOrderResponse{}
+	mi := &file_shop_shop_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakeOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeOrderResponse) ProtoMessage() {}
+
+func (x *MakeOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeOrderResponse.ProtoReflect.Descriptor instead.
+func (*MakeOrderResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MakeOrderResponse) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *MakeOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MakeOrderResponse) GetPaymentURL() string {
+	if x != nil {
+		return x.PaymentURL
+	}
+	return ""
+}
+
+func (x *MakeOrderResponse) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *MakeOrderResponse) GetSum() float32 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *MakeOrderResponse) GetSumMoney() *Money {
+	if x != nil {
+		return x.SumMoney
+	}
+	return nil
+}
+
+type OrderItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Заполняются сервером: название и цена на момент заказа
+	ProductName string `protobuf:"bytes,3,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Price float32 `protobuf:"fixed32,4,opt,name=price,proto3" json:"price,omitempty"` // используйте price_money
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Sum float32 `protobuf:"fixed32,5,opt,name=sum,proto3" json:"sum,omitempty"` // используйте sum_money
+	// Обязателен для товаров с вариантами
+	VariantId     int64  `protobuf:"varint,6,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku           string `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"` // заполняется сервером
+	PriceMoney    *Money `protobuf:"bytes,8,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"`
+	SumMoney      *Money `protobuf:"bytes,9,opt,name=sum_money,json=sumMoney,proto3" json:"sum_money,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_shop_shop_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OrderItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *OrderItem) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *OrderItem) GetSum() float32 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *OrderItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItem) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+func (x *OrderItem) GetSumMoney() *Money {
+	if x != nil {
+		return x.SumMoney
+	}
+	return nil
+}
+
+type OrdersHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // необязателен, берется из токена; другой id -> PermissionDenied
+	// По умолчанию 20, не больше 100
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token из предыдущего ответа; фильтры при листании не меняются
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Фильтры, пустые значения не фильтруют
+	Statuses      []string `protobuf:"bytes,4,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	FromTime      string   `protobuf:"bytes,5,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"` // RFC 3339, включительно
+	ToTime        string   `protobuf:"bytes,6,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`       // RFC 3339, не включительно
+	ProductId     int64    `protobuf:"varint,7,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrdersHistoryRequest) Reset() {
+	*x = OrdersHistoryRequest{}
+	mi := &file_shop_shop_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrdersHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdersHistoryRequest) ProtoMessage() {}
+
+func (x *OrdersHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdersHistoryRequest.ProtoReflect.Descriptor instead.
+func (*OrdersHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OrdersHistoryRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrdersHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *OrdersHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *OrdersHistoryRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *OrdersHistoryRequest) GetFromTime() string {
+	if x != nil {
+		return x.FromTime
+	}
+	return ""
+}
+
+func (x *OrdersHistoryRequest) GetToTime() string {
+	if x != nil {
+		return x.ToTime
+	}
+	return ""
+}
+
+func (x *OrdersHistoryRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type OrdersHistoryResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Orders []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	// Пустой на последней странице
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrdersHistoryResponse) Reset() {
+	*x = OrdersHistoryResponse{}
+	mi := &file_shop_shop_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrdersHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdersHistoryResponse) ProtoMessage() {}
+
+func (x *OrdersHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdersHistoryResponse.ProtoReflect.Descriptor instead.
+func (*OrdersHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *OrdersHistoryResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *OrdersHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Order struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId    int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId int64                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Sum       float32      `protobuf:"fixed32,5,opt,name=sum,proto3" json:"sum,omitempty"` // используйте sum_money
+	OrderTime string       `protobuf:"bytes,6,opt,name=order_time,json=orderTime,proto3" json:"order_time,omitempty"`
+	Status    string       `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"` // reserved, paid, shipped, delivered, canceled, expired, refunded
+	Items     []*OrderItem `protobuf:"bytes,8,rep,name=items,proto3" json:"items,omitempty"`
+	// Заполняются только в GetOrder
+	ExpiresAt      string               `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // до какого времени нужно оплатить резервацию
+	StatusHistory  []*OrderStatusChange `protobuf:"bytes,10,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	Payment        *PaymentInfo         `protobuf:"bytes,11,opt,name=payment,proto3" json:"payment,omitempty"`
+	SumMoney       *Money               `protobuf:"bytes,12,opt,name=sum_money,json=sumMoney,proto3" json:"sum_money,omitempty"`
+	RefundedAmount *Money               `protobuf:"bytes,13,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"` // сумма возвратов денег, кроме неуспешных
+	Returns        []*Return            `protobuf:"bytes,14,rep,name=returns,proto3" json:"returns,omitempty"`                                     // только в GetOrder
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_shop_shop_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Order) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Order) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *Order) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *Order) GetSum() float32 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Order) GetOrderTime() string {
+	if x != nil {
+		return x.OrderTime
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Order) GetStatusHistory() []*OrderStatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *Order) GetPayment() *PaymentInfo {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *Order) GetSumMoney() *Money {
+	if x != nil {
+		return x.SumMoney
+	}
+	return nil
+}
+
+func (x *Order) GetRefundedAmount() *Money {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return nil
+}
+
+func (x *Order) GetReturns() []*Return {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+type OrderStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // пустой у создания заказа
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"` // user:<id>, system:expirer, payment
+	Time          string                 `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_shop_shop_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChange) ProtoMessage() {}
+
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *OrderStatusChange) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type PaymentInfo struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Status     string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                           // pending, paid, not_paid, refund_pending, partially_refunded, refunded
+	PaymentUrl string                 `protobuf:"bytes,2,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"` // только пока заказ ждет оплаты
+	PaidAt     string                 `protobuf:"bytes,3,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	RefundedAmount      float32    `protobuf:"fixed32,4,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"` // используйте refunded_amount_money
+	Refunds             []*Refund  `protobuf:"bytes,5,rep,name=refunds,proto3" json:"refunds,omitempty"`
+	RefundedAmountMoney *Money     `protobuf:"bytes,6,opt,name=refunded_amount_money,json=refundedAmountMoney,proto3" json:"refunded_amount_money,omitempty"`
+	Payments            []*Payment `protobuf:"bytes,7,rep,name=payments,proto3" json:"payments,omitempty"` // все сессии оплаты и попытки по ним
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PaymentInfo) Reset() {
+	*x = PaymentInfo{}
+	mi := &file_shop_shop_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentInfo) ProtoMessage() {}
+
+func (x *PaymentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentInfo.ProtoReflect.Descriptor instead.
+func (*PaymentInfo) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PaymentInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentInfo) GetPaymentUrl() string {
+	if x != nil {
+		return x.PaymentUrl
+	}
+	return ""
+}
+
+func (x *PaymentInfo) GetPaidAt() string {
+	if x != nil {
+		return x.PaidAt
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *PaymentInfo) GetRefundedAmount() float32 {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return 0
+}
+
+func (x *PaymentInfo) GetRefunds() []*Refund {
+	if x != nil {
+		return x.Refunds
+	}
+	return nil
+}
+
+func (x *PaymentInfo) GetRefundedAmountMoney() *Money {
+	if x != nil {
+		return x.RefundedAmountMoney
+	}
+	return nil
+}
+
+func (x *PaymentInfo) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+type Payment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // pending, succeeded, failed, refunded
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_shop_shop_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Payment) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payment) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Payment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Payment) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type Refund struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Amount        float32 `protobuf:"fixed32,2,opt,name=amount,proto3" json:"amount,omitempty"` // используйте amount_money
+	Status        string  `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`   // requested (ждет выплаты), processing, succeeded, failed
+	Reason        string  `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     string  `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AmountMoney   *Money  `protobuf:"bytes,6,opt,name=amount_money,json=amountMoney,proto3" json:"amount_money,omitempty"`
+	ReturnId      int64   `protobuf:"varint,7,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"` // заявка на возврат товара, если есть
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_shop_shop_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Refund) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *Refund) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Refund) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Refund) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Refund) GetAmountMoney() *Money {
+	if x != nil {
+		return x.AmountMoney
+	}
+	return nil
+}
+
+func (x *Refund) GetReturnId() int64 {
+	if x != nil {
+		return x.ReturnId
+	}
+	return 0
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_shop_shop_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetOrderRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type PaymentConfirmation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Success        bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // true если оплата прошла
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PaymentConfirmation) Reset() {
+	*x = PaymentConfirmation{}
+	mi := &file_shop_shop_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentConfirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentConfirmation) ProtoMessage() {}
+
+func (x *PaymentConfirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentConfirmation.ProtoReflect.Descriptor instead.
+func (*PaymentConfirmation) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PaymentConfirmation) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PaymentConfirmation) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PaymentConfirmation) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CreateProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	Price         float32 `protobuf:"fixed32,2,opt,name=price,proto3" json:"price,omitempty"` // используйте price_money
+	Stock         int32   `protobuf:"varint,3,opt,name=stock,proto3" json:"stock,omitempty"`
+	PriceMoney    *Money  `protobuf:"bytes,4,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"` // если задан, price не используется
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_shop_shop_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *CreateProductRequest) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+type UpdateProductRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Обновляемые поля: name, price, stock. Пустая маска - обновить все поля.
+	// Для price берется product.price_money, а если он не задан - product.price
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_shop_shop_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_shop_shop_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductRequest) ProtoMessage() {}
+
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteProductRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_shop_shop_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CancelOrderRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status  string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Заказ был оплачен: создана заявка на возврат денег
+	RefundRequested bool `protobuf:"varint,3,opt,name=refund_requested,json=refundRequested,proto3" json:"refund_requested,omitempty"`
+	// Deprecated: Marked as deprecated in shop/shop.proto.
+	RefundAmount      float32 `protobuf:"fixed32,4,opt,name=refund_amount,json=refundAmount,proto3" json:"refund_amount,omitempty"` // используйте refund_amount_money
+	RefundAmountMoney *Money  `protobuf:"bytes,5,opt,name=refund_amount_money,json=refundAmountMoney,proto3" json:"refund_amount_money,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_shop_shop_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
 
-func (x *GetProductInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[3]
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -197,64 +2384,70 @@ func (x *GetProductInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetProductInfoResponse) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *GetProductInfoResponse) GetProductId() int64 {
+func (x *CancelOrderResponse) GetOrderId() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.OrderId
 	}
 	return 0
 }
 
-func (x *GetProductInfoResponse) GetName() string {
+func (x *CancelOrderResponse) GetStatus() string {
 	if x != nil {
-		return x.Name
+		return x.Status
 	}
 	return ""
 }
 
-func (x *GetProductInfoResponse) GetPrice() float32 {
+func (x *CancelOrderResponse) GetRefundRequested() bool {
 	if x != nil {
-		return x.Price
+		return x.RefundRequested
 	}
-	return 0
+	return false
 }
 
-func (x *GetProductInfoResponse) GetStock() int32 {
+// Deprecated: Marked as deprecated in shop/shop.proto.
+func (x *CancelOrderResponse) GetRefundAmount() float32 {
 	if x != nil {
-		return x.Stock
+		return x.RefundAmount
 	}
 	return 0
 }
 
-type Product struct {
+func (x *CancelOrderResponse) GetRefundAmountMoney() *Money {
+	if x != nil {
+		return x.RefundAmountMoney
+	}
+	return nil
+}
+
+type UpdateOrderStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Price         float32                `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // shipped, delivered
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) Reset() {
-	*x = Product{}
-	mi := &file_shop_shop_proto_msgTypes[4]
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_shop_shop_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) String() string {
+func (x *UpdateOrderStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Product) ProtoMessage() {}
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
 
-func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[4]
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,63 +2458,49 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *Product) GetProductId() int64 {
+func (x *UpdateOrderStatusRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.OrderId
 	}
 	return 0
 }
 
-func (x *Product) GetName() string {
+func (x *UpdateOrderStatusRequest) GetStatus() string {
 	if x != nil {
-		return x.Name
+		return x.Status
 	}
 	return ""
 }
 
-func (x *Product) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
-func (x *Product) GetStock() int32 {
-	if x != nil {
-		return x.Stock
-	}
-	return 0
-}
-
-type MakeOrderRequest struct {
+type ReturnItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
 	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MakeOrderRequest) Reset() {
-	*x = MakeOrderRequest{}
-	mi := &file_shop_shop_proto_msgTypes[5]
+func (x *ReturnItem) Reset() {
+	*x = ReturnItem{}
+	mi := &file_shop_shop_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MakeOrderRequest) String() string {
+func (x *ReturnItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MakeOrderRequest) ProtoMessage() {}
+func (*ReturnItem) ProtoMessage() {}
 
-func (x *MakeOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[5]
+func (x *ReturnItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,56 +2511,61 @@ func (x *MakeOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MakeOrderRequest.ProtoReflect.Descriptor instead.
-func (*MakeOrderRequest) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ReturnItem.ProtoReflect.Descriptor instead.
+func (*ReturnItem) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *MakeOrderRequest) GetUserId() int64 {
+func (x *ReturnItem) GetProductId() int64 {
 	if x != nil {
-		return x.UserId
+		return x.ProductId
 	}
 	return 0
 }
 
-func (x *MakeOrderRequest) GetProductId() int64 {
+func (x *ReturnItem) GetVariantId() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.VariantId
 	}
 	return 0
 }
 
-func (x *MakeOrderRequest) GetQuantity() int32 {
+func (x *ReturnItem) GetQuantity() int32 {
 	if x != nil {
 		return x.Quantity
 	}
 	return 0
 }
 
-type MakeOrderResponse struct {
+type Return struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	PaymentURL    string                 `protobuf:"bytes,3,opt,name=paymentURL,proto3" json:"paymentURL,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // requested, approved, refunded
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Restocked     bool                   `protobuf:"varint,5,opt,name=restocked,proto3" json:"restocked,omitempty"`
+	Items         []*ReturnItem          `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,7,opt,name=amount,proto3" json:"amount,omitempty"` // стоимость строк по ценам заказа
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MakeOrderResponse) Reset() {
-	*x = MakeOrderResponse{}
-	mi := &file_shop_shop_proto_msgTypes[6]
+func (x *Return) Reset() {
+	*x = Return{}
+	mi := &file_shop_shop_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MakeOrderResponse) String() string {
+func (x *Return) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MakeOrderResponse) ProtoMessage() {}
+func (*Return) ProtoMessage() {}
 
-func (x *MakeOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[6]
+func (x *Return) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -392,54 +2576,91 @@ func (x *MakeOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MakeOrderResponse.ProtoReflect.Descriptor instead.
-func (*MakeOrderResponse) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Return.ProtoReflect.Descriptor instead.
+func (*Return) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *MakeOrderResponse) GetOrderId() int64 {
+func (x *Return) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Return) GetOrderId() int64 {
 	if x != nil {
 		return x.OrderId
 	}
 	return 0
 }
 
-func (x *MakeOrderResponse) GetStatus() string {
+func (x *Return) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-func (x *MakeOrderResponse) GetPaymentURL() string {
+func (x *Return) GetReason() string {
 	if x != nil {
-		return x.PaymentURL
+		return x.Reason
 	}
 	return ""
 }
 
-type OrdersHistoryRequest struct {
+func (x *Return) GetRestocked() bool {
+	if x != nil {
+		return x.Restocked
+	}
+	return false
+}
+
+func (x *Return) GetItems() []*ReturnItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Return) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Return) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type RequestReturnRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Items         []*ReturnItem          `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrdersHistoryRequest) Reset() {
-	*x = OrdersHistoryRequest{}
-	mi := &file_shop_shop_proto_msgTypes[7]
+func (x *RequestReturnRequest) Reset() {
+	*x = RequestReturnRequest{}
+	mi := &file_shop_shop_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrdersHistoryRequest) String() string {
+func (x *RequestReturnRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrdersHistoryRequest) ProtoMessage() {}
+func (*RequestReturnRequest) ProtoMessage() {}
 
-func (x *OrdersHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[7]
+func (x *RequestReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -450,40 +2671,55 @@ func (x *OrdersHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrdersHistoryRequest.ProtoReflect.Descriptor instead.
-func (*OrdersHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RequestReturnRequest.ProtoReflect.Descriptor instead.
+func (*RequestReturnRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *OrdersHistoryRequest) GetUserId() int64 {
+func (x *RequestReturnRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.UserId
+		return x.OrderId
 	}
 	return 0
 }
 
-type OrdersHistoryResponse struct {
+func (x *RequestReturnRequest) GetItems() []*ReturnItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *RequestReturnRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ApproveReturnRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	ReturnId      int64                  `protobuf:"varint,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	Restock       bool                   `protobuf:"varint,2,opt,name=restock,proto3" json:"restock,omitempty"` // вернуть товар на склад
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrdersHistoryResponse) Reset() {
-	*x = OrdersHistoryResponse{}
-	mi := &file_shop_shop_proto_msgTypes[8]
+func (x *ApproveReturnRequest) Reset() {
+	*x = ApproveReturnRequest{}
+	mi := &file_shop_shop_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrdersHistoryResponse) String() string {
+func (x *ApproveReturnRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrdersHistoryResponse) ProtoMessage() {}
+func (*ApproveReturnRequest) ProtoMessage() {}
 
-func (x *OrdersHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[8]
+func (x *ApproveReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -494,46 +2730,52 @@ func (x *OrdersHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrdersHistoryResponse.ProtoReflect.Descriptor instead.
-func (*OrdersHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ApproveReturnRequest.ProtoReflect.Descriptor instead.
+func (*ApproveReturnRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *OrdersHistoryResponse) GetOrders() []*Order {
+func (x *ApproveReturnRequest) GetReturnId() int64 {
 	if x != nil {
-		return x.Orders
+		return x.ReturnId
 	}
-	return nil
+	return 0
 }
 
-type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     int64                  `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Sum           float32                `protobuf:"fixed32,5,opt,name=sum,proto3" json:"sum,omitempty"`
-	OrderTime     string                 `protobuf:"bytes,6,opt,name=order_time,json=orderTime,proto3" json:"order_time,omitempty"`
-	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ApproveReturnRequest) GetRestock() bool {
+	if x != nil {
+		return x.Restock
+	}
+	return false
 }
 
-func (x *Order) Reset() {
-	*x = Order{}
-	mi := &file_shop_shop_proto_msgTypes[9]
+type RefundOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Сумма возврата. Не задана - сумма заявки return_id, а без заявки - весь остаток оплаты
+	Amount         *Money `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ReturnId       int64  `protobuf:"varint,3,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	Reason         string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefundOrderRequest) Reset() {
+	*x = RefundOrderRequest{}
+	mi := &file_shop_shop_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) String() string {
+func (x *RefundOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order) ProtoMessage() {}
+func (*RefundOrderRequest) ProtoMessage() {}
 
-func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[9]
+func (x *RefundOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,83 +2786,70 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *Order) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *Order) GetUserId() int64 {
-	if x != nil {
-		return x.UserId
-	}
-	return 0
+// Deprecated: Use RefundOrderRequest.ProtoReflect.Descriptor instead.
+func (*RefundOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *Order) GetProductId() int64 {
+func (x *RefundOrderRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.ProductId
+		return x.OrderId
 	}
 	return 0
 }
 
-func (x *Order) GetQuantity() int32 {
+func (x *RefundOrderRequest) GetAmount() *Money {
 	if x != nil {
-		return x.Quantity
+		return x.Amount
 	}
-	return 0
+	return nil
 }
 
-func (x *Order) GetSum() float32 {
+func (x *RefundOrderRequest) GetReturnId() int64 {
 	if x != nil {
-		return x.Sum
+		return x.ReturnId
 	}
 	return 0
 }
 
-func (x *Order) GetOrderTime() string {
+func (x *RefundOrderRequest) GetReason() string {
 	if x != nil {
-		return x.OrderTime
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *Order) GetStatus() string {
+func (x *RefundOrderRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.Status
+		return x.IdempotencyKey
 	}
 	return ""
 }
 
-type PaymentConfirmation struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // true если оплата прошла
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type RefundOrderResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Refund         *Refund                `protobuf:"bytes,1,opt,name=refund,proto3" json:"refund,omitempty"`
+	OrderStatus    string                 `protobuf:"bytes,2,opt,name=order_status,json=orderStatus,proto3" json:"order_status,omitempty"`
+	RefundedAmount *Money                 `protobuf:"bytes,3,opt,name=refunded_amount,json=refundedAmount,proto3" json:"refunded_amount,omitempty"` // всего возвращено по заказу
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *PaymentConfirmation) Reset() {
-	*x = PaymentConfirmation{}
-	mi := &file_shop_shop_proto_msgTypes[10]
+func (x *RefundOrderResponse) Reset() {
+	*x = RefundOrderResponse{}
+	mi := &file_shop_shop_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PaymentConfirmation) String() string {
+func (x *RefundOrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentConfirmation) ProtoMessage() {}
+func (*RefundOrderResponse) ProtoMessage() {}
 
-func (x *PaymentConfirmation) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[10]
+func (x *RefundOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shop_shop_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,23 +2860,30 @@ func (x *PaymentConfirmation) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentConfirmation.ProtoReflect.Descriptor instead.
-func (*PaymentConfirmation) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RefundOrderResponse.ProtoReflect.Descriptor instead.
+func (*RefundOrderResponse) Descriptor() ([]byte, []int) {
+	return file_shop_shop_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *PaymentConfirmation) GetOrderId() int64 {
+func (x *RefundOrderResponse) GetRefund() *Refund {
 	if x != nil {
-		return x.OrderId
+		return x.Refund
 	}
-	return 0
+	return nil
 }
 
-func (x *PaymentConfirmation) GetSuccess() bool {
+func (x *RefundOrderResponse) GetOrderStatus() string {
 	if x != nil {
-		return x.Success
+		return x.OrderStatus
 	}
-	return false
+	return ""
+}
+
+func (x *RefundOrderResponse) GetRefundedAmount() *Money {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return nil
 }
 
 type Empty struct {
@@ -658,7 +2894,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_shop_shop_proto_msgTypes[11]
+	mi := &file_shop_shop_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -670,7 +2906,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_shop_shop_proto_msgTypes[11]
+	mi := &file_shop_shop_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -683,69 +2919,287 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_shop_shop_proto_rawDescGZIP(), []int{11}
+	return file_shop_shop_proto_rawDescGZIP(), []int{41}
 }
 
 var File_shop_shop_proto protoreflect.FileDescriptor
 
 const file_shop_shop_proto_rawDesc = "" +
 	"\n" +
-	"\x0fshop/shop.proto\x12\x04shop\x1a\x1bgoogle/protobuf/empty.proto\"C\n" +
+	"\x0fshop/shop.proto\x12\x04shop\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\xd3\x01\n" +
 	"\x13ListProductsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"A\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12.\n" +
+	"\x13include_total_count\x18\x04 \x01(\bR\x11includeTotalCount\x12+\n" +
+	"\x06filter\x18\x05 \x01(\v2\x13.shop.ProductFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x06 \x01(\tR\x04sort\"\xcb\x02\n" +
+	"\rProductFilter\x12$\n" +
+	"\tmin_price\x18\x01 \x01(\x02B\x02\x18\x01H\x00R\bminPrice\x88\x01\x01\x12$\n" +
+	"\tmax_price\x18\x02 \x01(\x02B\x02\x18\x01H\x01R\bmaxPrice\x88\x01\x01\x12\"\n" +
+	"\rin_stock_only\x18\x03 \x01(\bR\vinStockOnly\x12#\n" +
+	"\rname_contains\x18\x04 \x01(\tR\fnameContains\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\x03R\n" +
+	"categoryId\x123\n" +
+	"\x0fmin_price_money\x18\x06 \x01(\v2\v.shop.MoneyR\rminPriceMoney\x123\n" +
+	"\x0fmax_price_money\x18\a \x01(\v2\v.shop.MoneyR\rmaxPriceMoneyB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"p\n" +
+	"\bCategory\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\x03R\n" +
+	"categoryId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\"j\n" +
+	"\fCategoryNode\x12*\n" +
+	"\bcategory\x18\x01 \x01(\v2\x0e.shop.CategoryR\bcategory\x12.\n" +
+	"\bchildren\x18\x02 \x03(\v2\x12.shop.CategoryNodeR\bchildren\"F\n" +
+	"\x15ListCategoriesRequest\x12\x1b\n" +
+	"\tparent_id\x18\x01 \x01(\x03R\bparentId\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"H\n" +
+	"\x16ListCategoriesResponse\x12.\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x0e.shop.CategoryR\n" +
+	"categories\"1\n" +
+	"\x16GetCategoryTreeRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\x03R\x06rootId\"C\n" +
+	"\x17GetCategoryTreeResponse\x12(\n" +
+	"\x05roots\x18\x01 \x03(\v2\x12.shop.CategoryNodeR\x05roots\"\x8a\x01\n" +
 	"\x14ListProductsResponse\x12)\n" +
-	"\bproducts\x18\x01 \x03(\v2\r.shop.ProductR\bproducts\"6\n" +
+	"\bproducts\x18\x01 \x03(\v2\r.shop.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x03R\n" +
+	"totalCount\"C\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"Z\n" +
+	"\x16SearchProductsResponse\x12*\n" +
+	"\x04hits\x18\x01 \x03(\v2\x16.shop.ProductSearchHitR\x04hits\x12\x14\n" +
+	"\x05fuzzy\x18\x02 \x01(\bR\x05fuzzy\"i\n" +
+	"\x10ProductSearchHit\x12'\n" +
+	"\aproduct\x18\x01 \x01(\v2\r.shop.ProductR\aproduct\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x02R\x04rank\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"6\n" +
 	"\x15GetProductInfoRequest\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\x03R\tproductId\"w\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\"\xdb\x01\n" +
 	"\x16GetProductInfoResponse\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x02R\x05price\x12\x14\n" +
-	"\x05stock\x18\x04 \x01(\x05R\x05stock\"h\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\x05price\x18\x03 \x01(\x02B\x02\x18\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x04 \x01(\x05R\x05stock\x120\n" +
+	"\bvariants\x18\x05 \x03(\v2\x14.shop.ProductVariantR\bvariants\x12,\n" +
+	"\vprice_money\x18\x06 \x01(\v2\v.shop.MoneyR\n" +
+	"priceMoney\"\xa4\x02\n" +
+	"\x0eProductVariant\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x01 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12D\n" +
+	"\n" +
+	"attributes\x18\x03 \x03(\v2$.shop.ProductVariant.AttributesEntryR\n" +
+	"attributes\x12\x18\n" +
+	"\x05price\x18\x04 \x01(\x02B\x02\x18\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12,\n" +
+	"\vprice_money\x18\x06 \x01(\v2\v.shop.MoneyR\n" +
+	"priceMoney\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x01\n" +
 	"\aProduct\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x02R\x05price\x12\x14\n" +
-	"\x05stock\x18\x04 \x01(\x05R\x05stock\"f\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\x05price\x18\x03 \x01(\x02B\x02\x18\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x04 \x01(\x05R\x05stock\x12,\n" +
+	"\vprice_money\x18\x05 \x01(\v2\v.shop.MoneyR\n" +
+	"priceMoney\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xb6\x01\n" +
 	"\x10MakeOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"f\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12%\n" +
+	"\x05items\x18\x04 \x03(\v2\x0f.shop.OrderItemR\x05items\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\"\xcd\x01\n" +
 	"\x11MakeOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1e\n" +
 	"\n" +
 	"paymentURL\x18\x03 \x01(\tR\n" +
-	"paymentURL\"/\n" +
+	"paymentURL\x12%\n" +
+	"\x05items\x18\x04 \x03(\v2\x0f.shop.OrderItemR\x05items\x12\x14\n" +
+	"\x03sum\x18\x05 \x01(\x02B\x02\x18\x01R\x03sum\x12(\n" +
+	"\tsum_money\x18\x06 \x01(\v2\v.shop.MoneyR\bsumMoney\"\xa2\x02\n" +
+	"\tOrderItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12!\n" +
+	"\fproduct_name\x18\x03 \x01(\tR\vproductName\x12\x18\n" +
+	"\x05price\x18\x04 \x01(\x02B\x02\x18\x01R\x05price\x12\x14\n" +
+	"\x03sum\x18\x05 \x01(\x02B\x02\x18\x01R\x03sum\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x06 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\a \x01(\tR\x03sku\x12,\n" +
+	"\vprice_money\x18\b \x01(\v2\v.shop.MoneyR\n" +
+	"priceMoney\x12(\n" +
+	"\tsum_money\x18\t \x01(\v2\v.shop.MoneyR\bsumMoney\"\xdc\x01\n" +
 	"\x14OrdersHistoryRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\"<\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1a\n" +
+	"\bstatuses\x18\x04 \x03(\tR\bstatuses\x12\x1b\n" +
+	"\tfrom_time\x18\x05 \x01(\tR\bfromTime\x12\x17\n" +
+	"\ato_time\x18\x06 \x01(\tR\x06toTime\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\a \x01(\x03R\tproductId\"d\n" +
 	"\x15OrdersHistoryResponse\x12#\n" +
-	"\x06orders\x18\x01 \x03(\v2\v.shop.OrderR\x06orders\"\xb4\x01\n" +
+	"\x06orders\x18\x01 \x03(\v2\v.shop.OrderR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf3\x03\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x03 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x10\n" +
-	"\x03sum\x18\x05 \x01(\x02R\x03sum\x12\x1d\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x14\n" +
+	"\x03sum\x18\x05 \x01(\x02B\x02\x18\x01R\x03sum\x12\x1d\n" +
 	"\n" +
 	"order_time\x18\x06 \x01(\tR\torderTime\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"J\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12%\n" +
+	"\x05items\x18\b \x03(\v2\x0f.shop.OrderItemR\x05items\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\tR\texpiresAt\x12>\n" +
+	"\x0estatus_history\x18\n" +
+	" \x03(\v2\x17.shop.OrderStatusChangeR\rstatusHistory\x12+\n" +
+	"\apayment\x18\v \x01(\v2\x11.shop.PaymentInfoR\apayment\x12(\n" +
+	"\tsum_money\x18\f \x01(\v2\v.shop.MoneyR\bsumMoney\x124\n" +
+	"\x0frefunded_amount\x18\r \x01(\v2\v.shop.MoneyR\x0erefundedAmount\x12&\n" +
+	"\areturns\x18\x0e \x03(\v2\f.shop.ReturnR\areturns\"a\n" +
+	"\x11OrderStatusChange\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\tR\x04time\"\xa0\x02\n" +
+	"\vPaymentInfo\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1f\n" +
+	"\vpayment_url\x18\x02 \x01(\tR\n" +
+	"paymentUrl\x12\x17\n" +
+	"\apaid_at\x18\x03 \x01(\tR\x06paidAt\x12+\n" +
+	"\x0frefunded_amount\x18\x04 \x01(\x02B\x02\x18\x01R\x0erefundedAmount\x12&\n" +
+	"\arefunds\x18\x05 \x03(\v2\f.shop.RefundR\arefunds\x12?\n" +
+	"\x15refunded_amount_money\x18\x06 \x01(\v2\v.shop.MoneyR\x13refundedAmountMoney\x12)\n" +
+	"\bpayments\x18\a \x03(\v2\r.shop.PaymentR\bpayments\"\xed\x01\n" +
+	"\aPayment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x1f\n" +
+	"\vexternal_id\x18\x03 \x01(\tR\n" +
+	"externalId\x12#\n" +
+	"\x06amount\x18\x04 \x01(\v2\v.shop.MoneyR\x06amount\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\"\xd0\x01\n" +
+	"\x06Refund\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\x06amount\x18\x02 \x01(\x02B\x02\x18\x01R\x06amount\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12.\n" +
+	"\famount_money\x18\x06 \x01(\v2\v.shop.MoneyR\vamountMoney\x12\x1b\n" +
+	"\treturn_id\x18\a \x01(\x03R\breturnId\",\n" +
+	"\x0fGetOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"s\n" +
 	"\x13PaymentConfirmation\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\"\a\n" +
-	"\x05Empty2\xf1\x02\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\x88\x01\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\x05price\x18\x02 \x01(\x02B\x02\x18\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x03 \x01(\x05R\x05stock\x12,\n" +
+	"\vprice_money\x18\x04 \x01(\v2\v.shop.MoneyR\n" +
+	"priceMoney\"|\n" +
+	"\x14UpdateProductRequest\x12'\n" +
+	"\aproduct\x18\x01 \x01(\v2\r.shop.ProductR\aproduct\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"5\n" +
+	"\x14DeleteProductRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\"G\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xd9\x01\n" +
+	"\x13CancelOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12)\n" +
+	"\x10refund_requested\x18\x03 \x01(\bR\x0frefundRequested\x12'\n" +
+	"\rrefund_amount\x18\x04 \x01(\x02B\x02\x18\x01R\frefundAmount\x12;\n" +
+	"\x13refund_amount_money\x18\x05 \x01(\v2\v.shop.MoneyR\x11refundAmountMoney\"M\n" +
+	"\x18UpdateOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"f\n" +
+	"\n" +
+	"ReturnItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\xed\x01\n" +
+	"\x06Return\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1c\n" +
+	"\trestocked\x18\x05 \x01(\bR\trestocked\x12&\n" +
+	"\x05items\x18\x06 \x03(\v2\x10.shop.ReturnItemR\x05items\x12#\n" +
+	"\x06amount\x18\a \x01(\v2\v.shop.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\"q\n" +
+	"\x14RequestReturnRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12&\n" +
+	"\x05items\x18\x02 \x03(\v2\x10.shop.ReturnItemR\x05items\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"M\n" +
+	"\x14ApproveReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\x03R\breturnId\x12\x18\n" +
+	"\arestock\x18\x02 \x01(\bR\arestock\"\xb2\x01\n" +
+	"\x12RefundOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12#\n" +
+	"\x06amount\x18\x02 \x01(\v2\v.shop.MoneyR\x06amount\x12\x1b\n" +
+	"\treturn_id\x18\x03 \x01(\x03R\breturnId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\"\x94\x01\n" +
+	"\x13RefundOrderResponse\x12$\n" +
+	"\x06refund\x18\x01 \x01(\v2\f.shop.RefundR\x06refund\x12!\n" +
+	"\forder_status\x18\x02 \x01(\tR\vorderStatus\x124\n" +
+	"\x0frefunded_amount\x18\x03 \x01(\v2\v.shop.MoneyR\x0erefundedAmount\"\a\n" +
+	"\x05Empty2\x88\t\n" +
 	"\vShopService\x12E\n" +
 	"\fListProducts\x12\x19.shop.ListProductsRequest\x1a\x1a.shop.ListProductsResponse\x12K\n" +
-	"\x0eGetProductInfo\x12\x1b.shop.GetProductInfoRequest\x1a\x1c.shop.GetProductInfoResponse\x12<\n" +
+	"\x0eGetProductInfo\x12\x1b.shop.GetProductInfoRequest\x1a\x1c.shop.GetProductInfoResponse\x12K\n" +
+	"\x0eSearchProducts\x12\x1b.shop.SearchProductsRequest\x1a\x1c.shop.SearchProductsResponse\x12K\n" +
+	"\x0eListCategories\x12\x1b.shop.ListCategoriesRequest\x1a\x1c.shop.ListCategoriesResponse\x12N\n" +
+	"\x0fGetCategoryTree\x12\x1c.shop.GetCategoryTreeRequest\x1a\x1d.shop.GetCategoryTreeResponse\x12<\n" +
 	"\tMakeOrder\x12\x16.shop.MakeOrderRequest\x1a\x17.shop.MakeOrderResponse\x12K\n" +
 	"\x10GetOrdersHistory\x12\x1a.shop.OrdersHistoryRequest\x1a\x1b.shop.OrdersHistoryResponse\x12C\n" +
-	"\x0eConfirmPayment\x12\x19.shop.PaymentConfirmation\x1a\x16.google.protobuf.EmptyB\x1cZ\x1akavshevnova.shop.v1;shopv1b\x06proto3"
+	"\x0eConfirmPayment\x12\x19.shop.PaymentConfirmation\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\vCancelOrder\x12\x18.shop.CancelOrderRequest\x1a\x19.shop.CancelOrderResponse\x12.\n" +
+	"\bGetOrder\x12\x15.shop.GetOrderRequest\x1a\v.shop.Order\x12:\n" +
+	"\rCreateProduct\x12\x1a.shop.CreateProductRequest\x1a\r.shop.Product\x12:\n" +
+	"\rUpdateProduct\x12\x1a.shop.UpdateProductRequest\x1a\r.shop.Product\x12C\n" +
+	"\rDeleteProduct\x12\x1a.shop.DeleteProductRequest\x1a\x16.google.protobuf.Empty\x12@\n" +
+	"\x11UpdateOrderStatus\x12\x1e.shop.UpdateOrderStatusRequest\x1a\v.shop.Order\x129\n" +
+	"\rRequestReturn\x12\x1a.shop.RequestReturnRequest\x1a\f.shop.Return\x129\n" +
+	"\rApproveReturn\x12\x1a.shop.ApproveReturnRequest\x1a\f.shop.Return\x12B\n" +
+	"\vRefundOrder\x12\x18.shop.RefundOrderRequest\x1a\x19.shop.RefundOrderResponseB\x1cZ\x1akavshevnova.shop.v1;shopv1b\x06proto3"
 
 var (
 	file_shop_shop_proto_rawDescOnce sync.Once
@@ -759,40 +3213,136 @@ func file_shop_shop_proto_rawDescGZIP() []byte {
 	return file_shop_shop_proto_rawDescData
 }
 
-var file_shop_shop_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_shop_shop_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_shop_shop_proto_goTypes = []any{
-	(*ListProductsRequest)(nil),    // 0: shop.ListProductsRequest
-	(*ListProductsResponse)(nil),   // 1: shop.ListProductsResponse
-	(*GetProductInfoRequest)(nil),  // 2: shop.GetProductInfoRequest
-	(*GetProductInfoResponse)(nil), // 3: shop.GetProductInfoResponse
-	(*Product)(nil),                // 4: shop.Product
-	(*MakeOrderRequest)(nil),       // 5: shop.MakeOrderRequest
-	(*MakeOrderResponse)(nil),      // 6: shop.MakeOrderResponse
-	(*OrdersHistoryRequest)(nil),   // 7: shop.OrdersHistoryRequest
-	(*OrdersHistoryResponse)(nil),  // 8: shop.OrdersHistoryResponse
-	(*Order)(nil),                  // 9: shop.Order
-	(*PaymentConfirmation)(nil),    // 10: shop.PaymentConfirmation
-	(*Empty)(nil),                  // 11: shop.Empty
-	(*emptypb.Empty)(nil),          // 12: google.protobuf.Empty
+	(*ListProductsRequest)(nil),      // 0: shop.ListProductsRequest
+	(*ProductFilter)(nil),            // 1: shop.ProductFilter
+	(*Category)(nil),                 // 2: shop.Category
+	(*CategoryNode)(nil),             // 3: shop.CategoryNode
+	(*ListCategoriesRequest)(nil),    // 4: shop.ListCategoriesRequest
+	(*ListCategoriesResponse)(nil),   // 5: shop.ListCategoriesResponse
+	(*GetCategoryTreeRequest)(nil),   // 6: shop.GetCategoryTreeRequest
+	(*GetCategoryTreeResponse)(nil),  // 7: shop.GetCategoryTreeResponse
+	(*ListProductsResponse)(nil),     // 8: shop.ListProductsResponse
+	(*SearchProductsRequest)(nil),    // 9: shop.SearchProductsRequest
+	(*SearchProductsResponse)(nil),   // 10: shop.SearchProductsResponse
+	(*ProductSearchHit)(nil),         // 11: shop.ProductSearchHit
+	(*GetProductInfoRequest)(nil),    // 12: shop.GetProductInfoRequest
+	(*GetProductInfoResponse)(nil),   // 13: shop.GetProductInfoResponse
+	(*ProductVariant)(nil),           // 14: shop.ProductVariant
+	(*Product)(nil),                  // 15: shop.Product
+	(*Money)(nil),                    // 16: shop.Money
+	(*MakeOrderRequest)(nil),         // 17: shop.MakeOrderRequest
+	(*MakeOrderResponse)(nil),        // 18: shop.MakeOrderResponse
+	(*OrderItem)(nil),                // 19: shop.OrderItem
+	(*OrdersHistoryRequest)(nil),     // 20: shop.OrdersHistoryRequest
+	(*OrdersHistoryResponse)(nil),    // 21: shop.OrdersHistoryResponse
+	(*Order)(nil),                    // 22: shop.Order
+	(*OrderStatusChange)(nil),        // 23: shop.OrderStatusChange
+	(*PaymentInfo)(nil),              // 24: shop.PaymentInfo
+	(*Payment)(nil),                  // 25: shop.Payment
+	(*Refund)(nil),                   // 26: shop.Refund
+	(*GetOrderRequest)(nil),          // 27: shop.GetOrderRequest
+	(*PaymentConfirmation)(nil),      // 28: shop.PaymentConfirmation
+	(*CreateProductRequest)(nil),     // 29: shop.CreateProductRequest
+	(*UpdateProductRequest)(nil),     // 30: shop.UpdateProductRequest
+	(*DeleteProductRequest)(nil),     // 31: shop.DeleteProductRequest
+	(*CancelOrderRequest)(nil),       // 32: shop.CancelOrderRequest
+	(*CancelOrderResponse)(nil),      // 33: shop.CancelOrderResponse
+	(*UpdateOrderStatusRequest)(nil), // 34: shop.UpdateOrderStatusRequest
+	(*ReturnItem)(nil),               // 35: shop.ReturnItem
+	(*Return)(nil),                   // 36: shop.Return
+	(*RequestReturnRequest)(nil),     // 37: shop.RequestReturnRequest
+	(*ApproveReturnRequest)(nil),     // 38: shop.ApproveReturnRequest
+	(*RefundOrderRequest)(nil),       // 39: shop.RefundOrderRequest
+	(*RefundOrderResponse)(nil),      // 40: shop.RefundOrderResponse
+	(*Empty)(nil),                    // 41: shop.Empty
+	nil,                              // 42: shop.ProductVariant.AttributesEntry
+	(*fieldmaskpb.FieldMask)(nil),    // 43: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),            // 44: google.protobuf.Empty
 }
 var file_shop_shop_proto_depIdxs = []int32{
-	4,  // 0: shop.ListProductsResponse.products:type_name -> shop.Product
-	9,  // 1: shop.OrdersHistoryResponse.orders:type_name -> shop.Order
-	0,  // 2: shop.ShopService.ListProducts:input_type -> shop.ListProductsRequest
-	2,  // 3: shop.ShopService.GetProductInfo:input_type -> shop.GetProductInfoRequest
-	5,  // 4: shop.ShopService.MakeOrder:input_type -> shop.MakeOrderRequest
-	7,  // 5: shop.ShopService.GetOrdersHistory:input_type -> shop.OrdersHistoryRequest
-	10, // 6: shop.ShopService.ConfirmPayment:input_type -> shop.PaymentConfirmation
-	1,  // 7: shop.ShopService.ListProducts:output_type -> shop.ListProductsResponse
-	3,  // 8: shop.ShopService.GetProductInfo:output_type -> shop.GetProductInfoResponse
-	6,  // 9: shop.ShopService.MakeOrder:output_type -> shop.MakeOrderResponse
-	8,  // 10: shop.ShopService.GetOrdersHistory:output_type -> shop.OrdersHistoryResponse
-	12, // 11: shop.ShopService.ConfirmPayment:output_type -> google.protobuf.Empty
-	7,  // [7:12] is the sub-list for method output_type
-	2,  // [2:7] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	1,  // 0: shop.ListProductsRequest.filter:type_name -> shop.ProductFilter
+	16, // 1: shop.ProductFilter.min_price_money:type_name -> shop.Money
+	16, // 2: shop.ProductFilter.max_price_money:type_name -> shop.Money
+	2,  // 3: shop.CategoryNode.category:type_name -> shop.Category
+	3,  // 4: shop.CategoryNode.children:type_name -> shop.CategoryNode
+	2,  // 5: shop.ListCategoriesResponse.categories:type_name -> shop.Category
+	3,  // 6: shop.GetCategoryTreeResponse.roots:type_name -> shop.CategoryNode
+	15, // 7: shop.ListProductsResponse.products:type_name -> shop.Product
+	11, // 8: shop.SearchProductsResponse.hits:type_name -> shop.ProductSearchHit
+	15, // 9: shop.ProductSearchHit.product:type_name -> shop.Product
+	14, // 10: shop.GetProductInfoResponse.variants:type_name -> shop.ProductVariant
+	16, // 11: shop.GetProductInfoResponse.price_money:type_name -> shop.Money
+	42, // 12: shop.ProductVariant.attributes:type_name -> shop.ProductVariant.AttributesEntry
+	16, // 13: shop.ProductVariant.price_money:type_name -> shop.Money
+	16, // 14: shop.Product.price_money:type_name -> shop.Money
+	19, // 15: shop.MakeOrderRequest.items:type_name -> shop.OrderItem
+	19, // 16: shop.MakeOrderResponse.items:type_name -> shop.OrderItem
+	16, // 17: shop.MakeOrderResponse.sum_money:type_name -> shop.Money
+	16, // 18: shop.OrderItem.price_money:type_name -> shop.Money
+	16, // 19: shop.OrderItem.sum_money:type_name -> shop.Money
+	22, // 20: shop.OrdersHistoryResponse.orders:type_name -> shop.Order
+	19, // 21: shop.Order.items:type_name -> shop.OrderItem
+	23, // 22: shop.Order.status_history:type_name -> shop.OrderStatusChange
+	24, // 23: shop.Order.payment:type_name -> shop.PaymentInfo
+	16, // 24: shop.Order.sum_money:type_name -> shop.Money
+	16, // 25: shop.Order.refunded_amount:type_name -> shop.Money
+	36, // 26: shop.Order.returns:type_name -> shop.Return
+	26, // 27: shop.PaymentInfo.refunds:type_name -> shop.Refund
+	16, // 28: shop.PaymentInfo.refunded_amount_money:type_name -> shop.Money
+	25, // 29: shop.PaymentInfo.payments:type_name -> shop.Payment
+	16, // 30: shop.Payment.amount:type_name -> shop.Money
+	16, // 31: shop.Refund.amount_money:type_name -> shop.Money
+	16, // 32: shop.CreateProductRequest.price_money:type_name -> shop.Money
+	15, // 33: shop.UpdateProductRequest.product:type_name -> shop.Product
+	43, // 34: shop.UpdateProductRequest.update_mask:type_name -> google.protobuf.FieldMask
+	16, // 35: shop.CancelOrderResponse.refund_amount_money:type_name -> shop.Money
+	35, // 36: shop.Return.items:type_name -> shop.ReturnItem
+	16, // 37: shop.Return.amount:type_name -> shop.Money
+	35, // 38: shop.RequestReturnRequest.items:type_name -> shop.ReturnItem
+	16, // 39: shop.RefundOrderRequest.amount:type_name -> shop.Money
+	26, // 40: shop.RefundOrderResponse.refund:type_name -> shop.Refund
+	16, // 41: shop.RefundOrderResponse.refunded_amount:type_name -> shop.Money
+	0,  // 42: shop.ShopService.ListProducts:input_type -> shop.ListProductsRequest
+	12, // 43: shop.ShopService.GetProductInfo:input_type -> shop.GetProductInfoRequest
+	9,  // 44: shop.ShopService.SearchProducts:input_type -> shop.SearchProductsRequest
+	4,  // 45: shop.ShopService.ListCategories:input_type -> shop.ListCategoriesRequest
+	6,  // 46: shop.ShopService.GetCategoryTree:input_type -> shop.GetCategoryTreeRequest
+	17, // 47: shop.ShopService.MakeOrder:input_type -> shop.MakeOrderRequest
+	20, // 48: shop.ShopService.GetOrdersHistory:input_type -> shop.OrdersHistoryRequest
+	28, // 49: shop.ShopService.ConfirmPayment:input_type -> shop.PaymentConfirmation
+	32, // 50: shop.ShopService.CancelOrder:input_type -> shop.CancelOrderRequest
+	27, // 51: shop.ShopService.GetOrder:input_type -> shop.GetOrderRequest
+	29, // 52: shop.ShopService.CreateProduct:input_type -> shop.CreateProductRequest
+	30, // 53: shop.ShopService.UpdateProduct:input_type -> shop.UpdateProductRequest
+	31, // 54: shop.ShopService.DeleteProduct:input_type -> shop.DeleteProductRequest
+	34, // 55: shop.ShopService.UpdateOrderStatus:input_type -> shop.UpdateOrderStatusRequest
+	37, // 56: shop.ShopService.RequestReturn:input_type -> shop.RequestReturnRequest
+	38, // 57: shop.ShopService.ApproveReturn:input_type -> shop.ApproveReturnRequest
+	39, // 58: shop.ShopService.RefundOrder:input_type -> shop.RefundOrderRequest
+	8,  // 59: shop.ShopService.ListProducts:output_type -> shop.ListProductsResponse
+	13, // 60: shop.ShopService.GetProductInfo:output_type -> shop.GetProductInfoResponse
+	10, // 61: shop.ShopService.SearchProducts:output_type -> shop.SearchProductsResponse
+	5,  // 62: shop.ShopService.ListCategories:output_type -> shop.ListCategoriesResponse
+	7,  // 63: shop.ShopService.GetCategoryTree:output_type -> shop.GetCategoryTreeResponse
+	18, // 64: shop.ShopService.MakeOrder:output_type -> shop.MakeOrderResponse
+	21, // 65: shop.ShopService.GetOrdersHistory:output_type -> shop.OrdersHistoryResponse
+	44, // 66: shop.ShopService.ConfirmPayment:output_type -> google.protobuf.Empty
+	33, // 67: shop.ShopService.CancelOrder:output_type -> shop.CancelOrderResponse
+	22, // 68: shop.ShopService.GetOrder:output_type -> shop.Order
+	15, // 69: shop.ShopService.CreateProduct:output_type -> shop.Product
+	15, // 70: shop.ShopService.UpdateProduct:output_type -> shop.Product
+	44, // 71: shop.ShopService.DeleteProduct:output_type -> google.protobuf.Empty
+	22, // 72: shop.ShopService.UpdateOrderStatus:output_type -> shop.Order
+	36, // 73: shop.ShopService.RequestReturn:output_type -> shop.Return
+	36, // 74: shop.ShopService.ApproveReturn:output_type -> shop.Return
+	40, // 75: shop.ShopService.RefundOrder:output_type -> shop.RefundOrderResponse
+	59, // [59:76] is the sub-list for method output_type
+	42, // [42:59] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_shop_shop_proto_init() }
@@ -800,13 +3350,14 @@ func file_shop_shop_proto_init() {
 	if File_shop_shop_proto != nil {
 		return
 	}
+	file_shop_shop_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shop_shop_proto_rawDesc), len(file_shop_shop_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS order_items (
+    order_item_id BIGSERIAL PRIMARY KEY,
+    order_id      BIGINT NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    product_id    INTEGER NOT NULL REFERENCES products(product_id),
+    product_name  VARCHAR(255) NOT NULL,
+    quantity      INTEGER NOT NULL CHECK (quantity > 0),
+    price         DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    sum           DECIMAL(10,2) NOT NULL CHECK (sum >= 0),
+    UNIQUE (order_id, product_id)
+);
+
+-- Переносим существующие заказы в строки заказа
+INSERT INTO order_items (order_id, product_id, product_name, quantity, price, sum)
+SELECT o.order_id, o.product_id, p.name, o.quantity, ROUND(o.sum / o.quantity, 2), o.sum
+FROM orders o JOIN products p ON p.product_id = o.product_id;
+
+-- В orders товар и количество заполняются только для заказов из одной строки
+ALTER TABLE orders ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE orders ALTER COLUMN quantity DROP NOT NULL;
+
+-- +goose Down
+UPDATE orders o SET product_id = oi.product_id, quantity = oi.quantity
+FROM (SELECT DISTINCT ON (order_id) order_id, product_id, quantity FROM order_items ORDER BY order_id, order_item_id) oi
+WHERE o.order_id = oi.order_id AND o.product_id IS NULL;
+ALTER TABLE orders ALTER COLUMN product_id SET NOT NULL;
+ALTER TABLE orders ALTER COLUMN quantity SET NOT NULL;
+DROP TABLE IF EXISTS order_items;
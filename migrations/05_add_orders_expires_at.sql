@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- Уже висящие резервации истекают через стандартные 15 минут от создания
+UPDATE orders SET expires_at = time + INTERVAL '15 minutes' WHERE status = 'reserved';
+
+CREATE INDEX IF NOT EXISTS orders_reserved_expires_at_idx ON orders (expires_at) WHERE status = 'reserved';
+
+-- +goose Down
+DROP INDEX IF EXISTS orders_reserved_expires_at_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS expires_at;
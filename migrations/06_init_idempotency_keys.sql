@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id     BIGINT NOT NULL,
+    method      VARCHAR(100) NOT NULL,
+    key         VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    response    BYTEA,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, method, key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
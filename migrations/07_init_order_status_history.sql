@@ -0,0 +1,33 @@
+-- +goose Up
+-- CHAR(20) дополняет статус пробелами, переходим на VARCHAR и фиксируем набор статусов
+DROP INDEX IF EXISTS orders_reserved_expires_at_idx;
+ALTER TABLE orders ALTER COLUMN status TYPE VARCHAR(20) USING TRIM(status);
+UPDATE orders SET status = 'paid' WHERE status = 'confirmed';
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('reserved', 'paid', 'shipped', 'delivered', 'canceled', 'expired', 'refunded'));
+CREATE INDEX IF NOT EXISTS orders_reserved_expires_at_idx ON orders (expires_at) WHERE status = 'reserved';
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status   VARCHAR(20) NOT NULL,
+    actor       VARCHAR(100) NOT NULL,
+    time        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_id_idx ON order_status_history (order_id, time);
+
+-- Для существующих заказов известно только создание и текущий статус
+INSERT INTO order_status_history (order_id, from_status, to_status, actor, time)
+SELECT order_id, NULL, 'reserved', 'migration', time FROM orders;
+INSERT INTO order_status_history (order_id, from_status, to_status, actor, time)
+SELECT order_id, 'reserved', status, 'migration', time FROM orders WHERE status <> 'reserved';
+
+-- +goose Down
+DROP TABLE IF EXISTS order_status_history;
+DROP INDEX IF EXISTS orders_reserved_expires_at_idx;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+UPDATE orders SET status = 'confirmed' WHERE status = 'paid';
+ALTER TABLE orders ALTER COLUMN status TYPE CHAR(20);
+CREATE INDEX IF NOT EXISTS orders_reserved_expires_at_idx ON orders (expires_at) WHERE status = 'reserved';
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS refunds (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   BIGINT NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    amount     DECIMAL(10, 2) NOT NULL,
+    status     VARCHAR(20) NOT NULL DEFAULT 'requested',
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS refunds_order_id_idx ON refunds (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS refunds;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- История заказов пользователя листается по (time, order_id) по убыванию
+CREATE INDEX IF NOT EXISTS orders_user_id_time_idx ON orders (user_id, time DESC, order_id DESC);
+-- Фильтр истории по товару
+CREATE INDEX IF NOT EXISTS order_items_product_id_idx ON order_items (product_id, order_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS order_items_product_id_idx;
+DROP INDEX IF EXISTS orders_user_id_time_idx;
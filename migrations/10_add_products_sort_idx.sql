@@ -0,0 +1,8 @@
+-- +goose Up
+-- Сортировки каталога листаются по (колонка, product_id)
+CREATE INDEX IF NOT EXISTS products_price_idx ON products (price, product_id);
+CREATE INDEX IF NOT EXISTS products_name_idx ON products (name, product_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS products_name_idx;
+DROP INDEX IF EXISTS products_price_idx;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS categories (
+    category_id BIGSERIAL PRIMARY KEY,
+    parent_id   BIGINT REFERENCES categories(category_id) ON DELETE RESTRICT,
+    name        VARCHAR(255) NOT NULL,
+    slug        VARCHAR(255) NOT NULL UNIQUE,
+    CHECK (parent_id <> category_id)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id  BIGINT NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    category_id BIGINT NOT NULL REFERENCES categories(category_id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS product_categories_category_id_idx ON product_categories (category_id, product_id);
+
+INSERT INTO categories (parent_id, name, slug) VALUES (NULL, 'Electronics', 'electronics');
+INSERT INTO categories (parent_id, name, slug)
+SELECT category_id, v.name, v.slug FROM categories,
+    (VALUES ('Computers', 'computers'), ('Phones', 'phones'), ('Wearables', 'wearables'), ('Audio', 'audio')) AS v(name, slug)
+WHERE categories.slug = 'electronics';
+INSERT INTO categories (parent_id, name, slug)
+SELECT category_id, v.name, v.slug FROM categories,
+    (VALUES ('Laptops', 'laptops'), ('Tablets', 'tablets')) AS v(name, slug)
+WHERE categories.slug = 'computers';
+
+INSERT INTO product_categories (product_id, category_id)
+SELECT p.product_id, c.category_id FROM products p
+JOIN (VALUES ('MacBook Pro', 'laptops'), ('iPhone 15', 'phones'), ('iPad Air', 'tablets'),
+             ('Apple Watch', 'wearables'), ('AirPods Pro', 'audio')) AS v(product, slug) ON v.product = p.name
+JOIN categories c ON c.slug = v.slug;
+
+-- +goose Down
+DROP TABLE IF EXISTS product_categories;
+DROP TABLE IF EXISTS categories;
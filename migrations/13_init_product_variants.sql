@@ -0,0 +1,26 @@
+-- +goose Up
+-- Вариант товара (размер, цвет) со своим SKU и остатком. price NULL - цена берется из товара
+CREATE TABLE IF NOT EXISTS product_variants (
+    variant_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    sku        VARCHAR(64) NOT NULL UNIQUE,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    price      DECIMAL(10,2) CHECK (price >= 0),
+    stock      INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS product_variants_product_id_idx ON product_variants (product_id, variant_id);
+
+-- Строка заказа ссылается на вариант, если товар продается вариантами
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id BIGINT REFERENCES product_variants(variant_id);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_order_id_product_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS order_items_order_product_variant_idx ON order_items (order_id, product_id, COALESCE(variant_id, 0));
+
+-- +goose Down
+DROP INDEX IF EXISTS order_items_order_product_variant_idx;
+DELETE FROM order_items WHERE variant_id IS NOT NULL;
+ALTER TABLE order_items ADD CONSTRAINT order_items_order_id_product_id_key UNIQUE (order_id, product_id);
+ALTER TABLE order_items DROP COLUMN IF EXISTS sku;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS product_variants;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Уже обработанные события платежных вебхуков: повтор того же события отклоняется
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider    VARCHAR(50) NOT NULL,
+    event_id    VARCHAR(255) NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, event_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS payment_webhook_events;
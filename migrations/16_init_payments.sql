@@ -0,0 +1,22 @@
+-- +goose Up
+-- Платежи по заказам: сессия у провайдера и все попытки оплаты по ней
+CREATE TABLE IF NOT EXISTS payments (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    provider    VARCHAR(50) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    payment_url TEXT NOT NULL DEFAULT '',
+    amount      DECIMAL(10,2) NOT NULL,
+    currency    CHAR(3) NOT NULL DEFAULT 'USD',
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts    INT NOT NULL DEFAULT 0,
+    raw_payload JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS payments_order_id_idx ON payments (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS payments;
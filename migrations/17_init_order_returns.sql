@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS order_returns (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   BIGINT NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    status     VARCHAR(20) NOT NULL DEFAULT 'requested',
+    reason     TEXT NOT NULL DEFAULT '',
+    restocked  BOOLEAN NOT NULL DEFAULT FALSE,
+    amount     DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_returns_order_id_idx ON order_returns (order_id);
+
+CREATE TABLE IF NOT EXISTS order_return_items (
+    return_id  BIGINT NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    quantity   INT NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS order_return_items_return_id_idx ON order_return_items (return_id);
+
+-- Возврат денег может относиться к заявке на возврат товара; external_id - id возврата у провайдера
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS return_id BIGINT REFERENCES order_returns(id);
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS external_id VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE refunds DROP COLUMN IF EXISTS external_id;
+ALTER TABLE refunds DROP COLUMN IF EXISTS return_id;
+DROP TABLE IF EXISTS order_return_items;
+DROP TABLE IF EXISTS order_returns;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Доменные события пишутся в той же транзакции, что и изменение заказа, и публикуются relay-воркером
+CREATE TABLE IF NOT EXISTS outbox (
+    id             BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id   BIGINT NOT NULL,
+    event_type     VARCHAR(100) NOT NULL,
+    payload        JSONB NOT NULL,
+    attempts       INT NOT NULL DEFAULT 0,
+    last_error     TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
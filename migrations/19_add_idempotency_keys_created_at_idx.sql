@@ -0,0 +1,6 @@
+-- +goose Up
+-- Индекс для очистки истекших ключей идемпотентности
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys (created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idempotency_keys_created_at_idx;
@@ -2,28 +2,52 @@ package app
 
 import (
 	grpcapp "github.com/kavshevnova/product-reservation-system/pkg/app/grpc"
+	httpapp "github.com/kavshevnova/product-reservation-system/pkg/app/http"
+	"github.com/kavshevnova/product-reservation-system/pkg/config"
+	"github.com/kavshevnova/product-reservation-system/pkg/http/paymenthttp"
+	"github.com/kavshevnova/product-reservation-system/pkg/jwt"
+	"github.com/kavshevnova/product-reservation-system/pkg/payments/fakepayment"
+	"github.com/kavshevnova/product-reservation-system/pkg/publishers/memorypublisher"
+	"github.com/kavshevnova/product-reservation-system/pkg/publishers/redispublisher"
 	"github.com/kavshevnova/product-reservation-system/pkg/services/auth"
+	"github.com/kavshevnova/product-reservation-system/pkg/services/cart"
+	"github.com/kavshevnova/product-reservation-system/pkg/services/idempotency"
+	"github.com/kavshevnova/product-reservation-system/pkg/services/outbox"
 	"github.com/kavshevnova/product-reservation-system/pkg/services/shop"
 	"github.com/kavshevnova/product-reservation-system/pkg/storages/authstorage"
+	"github.com/kavshevnova/product-reservation-system/pkg/storages/cartstorage"
 	"github.com/kavshevnova/product-reservation-system/pkg/storages/shopstorage"
 	"log/slog"
+	"net/http"
 )
 
 type App struct {
 	GRPCsrv *grpcapp.App
+	HTTPsrv *httpapp.App
+	Relay   *outbox.Relay
+	Expirer *shop.ReservationExpirer
+	Cleaner *idempotency.Cleaner
 }
 
 func New(
 	log *slog.Logger,
 	grpcport int,
+	httpport int,
 	storagepath string,
+	redisCfg config.RedisConfig,
+	authCfg config.AuthConfig,
+	reservationCfg config.ReservationConfig,
+	paymentCfg config.PaymentConfig,
+	outboxCfg config.OutboxConfig,
+	idempotencyCfg config.IdempotencyConfig,
 ) *App {
 
-	addr := "redis:6380"
-	password := ""
-	db := 0
+	storageAuth, err := authstorage.NewUsersStorage(redisCfg.Addr, redisCfg.Password, redisCfg.DB)
+	if err != nil {
+		panic(err)
+	}
 
-	storageAuth, err := authstorage.NewUsersStorage(addr, password, db)
+	storageCart, err := cartstorage.NewCartStorage(redisCfg.Addr, redisCfg.Password, redisCfg.DB)
 	if err != nil {
 		panic(err)
 	}
@@ -33,12 +57,55 @@ func New(
 		panic(err)
 	}
 
-	authService := auth.New(log, storageAuth, storageAuth)
-	shopService := shop.New(log, storageShop, storageShop)
+	payments, checkout := newPaymentProvider(log, paymentCfg)
+
+	tokenIssuer := jwt.NewIssuer(authCfg.TokenKeyID, authCfg.TokenSecret, authCfg.AccessTokenTTL)
+	tokenVerifier := jwt.NewVerifier(authCfg.VerifierKeys())
+
+	authService := auth.New(log, storageAuth, storageAuth, tokenIssuer, storageAuth, authCfg.RefreshTokenTTL, authCfg.AdminEmails)
+	shopService := shop.New(log, storageShop, storageShop, storageShop, payments, storageShop, reservationCfg.TTL)
+	expirer := shop.NewReservationExpirer(log, storageShop, shopService, reservationCfg.SweepInterval, reservationCfg.SweepBatch)
+	cartService := cart.New(log, storageCart, storageShop, shopService)
+
+	publisher := newEventPublisher(log, redisCfg, outboxCfg)
+	relay := outbox.NewRelay(log, storageShop, publisher, outboxCfg.PollInterval, outboxCfg.Batch, outboxCfg.PublishTimeout)
+
+	cleaner := idempotency.NewCleaner(log, storageShop, idempotencyCfg.TTL, idempotencyCfg.CleanupInterval, idempotencyCfg.CleanupBatch)
 
-	grpcApp := grpcapp.New(log, authService, shopService, grpcport)
+	grpcApp := grpcapp.New(log, authService, shopService, cartService, storageShop, tokenVerifier, storageAuth, storageAuth, grpcport)
+
+	webhook := paymenthttp.NewWebhookHandler(log, shopService, storageShop, paymentCfg.Provider, paymentCfg.WebhookSecret, paymentCfg.WebhookTolerance)
+	httpApp := httpapp.New(log, webhook, checkout, httpport)
 
 	return &App{
 		GRPCsrv: grpcApp,
+		HTTPsrv: httpApp,
+		Relay:   relay,
+		Expirer: expirer,
+		Cleaner: cleaner,
+	}
+}
+
+// newPaymentProvider возвращает провайдера и, если провайдер локальный, его страницу оплаты
+func newPaymentProvider(log *slog.Logger, cfg config.PaymentConfig) (shop.PaymentProvider, http.Handler) {
+	switch cfg.Provider {
+	case fakepayment.ProviderName:
+		provider := fakepayment.New(cfg.FakeBaseURL)
+		return provider, fakepayment.NewCheckout(log, provider, cfg.FakeWebhookURL, cfg.WebhookSecret)
+	}
+	panic("unknown payment provider: " + cfg.Provider)
+}
+
+func newEventPublisher(log *slog.Logger, redisCfg config.RedisConfig, cfg config.OutboxConfig) outbox.EventPublisher {
+	switch cfg.Publisher {
+	case memorypublisher.PublisherName:
+		return memorypublisher.New(log, memorypublisher.DefaultCapacity)
+	case redispublisher.PublisherName:
+		publisher, err := redispublisher.New(redisCfg.Addr, redisCfg.Password, redisCfg.DB, cfg.Stream, cfg.StreamMaxLen)
+		if err != nil {
+			panic(err)
+		}
+		return publisher
 	}
+	panic("unknown outbox publisher: " + cfg.Publisher)
 }
@@ -3,6 +3,8 @@ package grpcapp
 import (
 	"fmt"
 	"github.com/kavshevnova/product-reservation-system/pkg/grpc/authgrpc"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/cartgrpc"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/idempotencygrpc"
 	"github.com/kavshevnova/product-reservation-system/pkg/grpc/shopgrpc"
 	"google.golang.org/grpc"
 	"log/slog"
@@ -19,11 +21,22 @@ func New(
 	logger *slog.Logger,
 	authService authgrpc.Auth,
 	shopService shopgrpc.Shop,
+	cartService cartgrpc.Cart,
+	idempotency idempotencygrpc.Store,
+	verifier TokenVerifier,
+	sessions SessionChecker,
+	users UserRolesProvider,
 	port int) *App {
-	grpcServer := grpc.NewServer()
-	//регистрируем оба сервиса на одном сервере
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			authInterceptor(logger, verifier, sessions),
+			rbacInterceptor(logger, users),
+		),
+	)
+	//регистрируем все сервисы на одном сервере
 	authgrpc.RegisterAuthServerAPI(grpcServer, authService)
-	shopgrpc.RegisterShopServerAPI(grpcServer, shopService)
+	shopgrpc.RegisterShopServerAPI(grpcServer, logger, shopService, idempotency)
+	cartgrpc.RegisterCartServerAPI(grpcServer, logger, cartService, idempotency)
 
 	return &App{
 		logger: logger,
@@ -0,0 +1,217 @@
+package grpcapp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	authv1 "github.com/kavshevnova/product-reservation-system/gen/go/auth"
+	shopv1 "github.com/kavshevnova/product-reservation-system/gen/go/shop"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type TokenVerifier interface {
+	Verify(token string) (*jwt.Claims, error)
+}
+
+type SessionChecker interface {
+	SessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+type UserRolesProvider interface {
+	UserByID(ctx context.Context, uid int64) (models.User, error)
+}
+
+// publicMethods - методы, доступные без access-токена: регистрация, вход, обновление токенов и просмотр каталога
+var publicMethods = map[string]bool{
+	authv1.AuthService_Register_FullMethodName:        true,
+	authv1.AuthService_Login_FullMethodName:           true,
+	authv1.AuthService_Refresh_FullMethodName:         true,
+	shopv1.ShopService_ListProducts_FullMethodName:    true,
+	shopv1.ShopService_GetProductInfo_FullMethodName:  true,
+	shopv1.ShopService_SearchProducts_FullMethodName:  true,
+	shopv1.ShopService_ListCategories_FullMethodName:  true,
+	shopv1.ShopService_GetCategoryTree_FullMethodName: true,
+}
+
+// methodRoles - таблица прав: роли, которым разрешен вызов метода.
+// Методы, которых нет в таблице, доступны любому аутентифицированному пользователю.
+var methodRoles = map[string][]models.Role{
+	authv1.AuthService_SetUserRoles_FullMethodName: {models.RoleAdmin},
+
+	shopv1.ShopService_CreateProduct_FullMethodName: {models.RoleAdmin},
+	shopv1.ShopService_UpdateProduct_FullMethodName: {models.RoleAdmin},
+	shopv1.ShopService_DeleteProduct_FullMethodName: {models.RoleAdmin},
+
+	shopv1.ShopService_UpdateOrderStatus_FullMethodName: {models.RoleSupport, models.RoleAdmin},
+
+	//Оплату подтверждает провайдер через подписанный вебхук, вручную - только администратор
+	shopv1.ShopService_ConfirmPayment_FullMethodName: {models.RoleAdmin},
+
+	shopv1.ShopService_ApproveReturn_FullMethodName: {models.RoleSupport, models.RoleAdmin},
+	shopv1.ShopService_RefundOrder_FullMethodName:   {models.RoleSupport, models.RoleAdmin},
+}
+
+// otherUserRoles - таблица прав на действия с чужим user_id из запроса: роль вызывающего ->
+// роли пользователей, над которыми она может действовать. Все роли цели должны быть разрешены,
+// поэтому поддержка не может завершить сессии администратора или другого сотрудника.
+// Для своего user_id правило не применяется.
+var otherUserRoles = map[string]map[models.Role][]models.Role{
+	authv1.AuthService_LogoutAll_FullMethodName: {
+		models.RoleSupport: {models.RoleCustomer},
+		models.RoleAdmin:   {models.RoleCustomer, models.RoleSupport, models.RoleAdmin},
+	},
+}
+
+// userScopedRequest - запрос, который может относиться к другому пользователю
+type userScopedRequest interface {
+	GetUserId() int64
+}
+
+// authInterceptor проверяет bearer-токен из метаданных и то, что его сессия не отозвана,
+// и кладет пользователя в контекст
+func authInterceptor(logger *slog.Logger, verifier TokenVerifier, sessions SessionChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		token, err := bearerToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			logger.Warn("invalid access token",
+				slog.String("method", info.FullMethod),
+				slog.String("error", err.Error()),
+			)
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		}
+
+		active, err := sessions.SessionActive(ctx, claims.SessionID)
+		if err != nil {
+			logger.Error("failed to check session",
+				slog.String("method", info.FullMethod),
+				slog.String("error", err.Error()),
+			)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if !active {
+			return nil, status.Error(codes.Unauthenticated, "session revoked")
+		}
+
+		ctx = authctx.WithPrincipal(ctx, models.Principal{
+			UserID:    claims.UserID,
+			Email:     claims.Email,
+			SessionID: claims.SessionID,
+			Roles:     claims.Roles,
+		})
+		return handler(ctx, req)
+	}
+}
+
+// rbacInterceptor проверяет роли пользователя по таблицам methodRoles и otherUserRoles.
+// Роли в access-токене могут устареть до обновления токенов, поэтому для методов из этих таблиц
+// роли вызывающего перечитываются из хранилища: снятая через SetUserRoles роль перестает действовать сразу.
+// Должен идти в цепочке после authInterceptor.
+func rbacInterceptor(logger *slog.Logger, users UserRolesProvider) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		roles, restricted := methodRoles[info.FullMethod]
+		targetRules, scoped := otherUserRoles[info.FullMethod]
+		if !restricted && !scoped {
+			return handler(ctx, req)
+		}
+
+		principal, ok := authctx.PrincipalFromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "authentication required")
+		}
+		caller, err := users.UserByID(ctx, principal.UserID)
+		if err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			}
+			logger.Error("failed to load caller roles",
+				slog.String("method", info.FullMethod),
+				slog.String("error", err.Error()),
+			)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		principal.Roles = caller.Roles
+		ctx = authctx.WithPrincipal(ctx, principal)
+
+		if restricted && !principal.HasAnyRole(roles...) {
+			logger.Warn("permission denied",
+				slog.String("method", info.FullMethod),
+				slog.Int64("user_id", principal.UserID),
+			)
+			return nil, status.Error(codes.PermissionDenied, "permission denied")
+		}
+
+		scopedReq, ok := req.(userScopedRequest)
+		if !scoped || !ok || scopedReq.GetUserId() == 0 || scopedReq.GetUserId() == principal.UserID {
+			return handler(ctx, req)
+		}
+		target, err := users.UserByID(ctx, scopedReq.GetUserId())
+		if err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				return nil, status.Error(codes.NotFound, "user not found")
+			}
+			logger.Error("failed to load target user",
+				slog.String("method", info.FullMethod),
+				slog.String("error", err.Error()),
+			)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if !canActOn(principal, target, targetRules) {
+			logger.Warn("permission denied for another user",
+				slog.String("method", info.FullMethod),
+				slog.Int64("user_id", principal.UserID),
+				slog.Int64("target_user_id", target.UserID),
+			)
+			return nil, status.Error(codes.PermissionDenied, "cannot act on behalf of this user")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// canActOn проверяет, что каждую роль цели разрешает хотя бы одна роль вызывающего
+func canActOn(principal models.Principal, target models.User, rules map[models.Role][]models.Role) bool {
+	allowed := make(map[models.Role]bool)
+	for _, role := range principal.Roles {
+		for _, targetRole := range rules[role] {
+			allowed[targetRole] = true
+		}
+	}
+	for _, role := range target.Roles {
+		if !allowed[role] {
+			return false
+		}
+	}
+	return len(allowed) > 0
+}
+
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization header must be a bearer token")
+	}
+	return token, nil
+}
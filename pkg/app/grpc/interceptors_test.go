@@ -0,0 +1,161 @@
+package grpcapp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	authv1 "github.com/kavshevnova/product-reservation-system/gen/go/auth"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	customerID = iota + 1
+	secondCustomerID
+	supportID
+	secondSupportID
+	adminID
+	secondAdminID
+	demotedAdminID
+)
+
+type fakeUsers map[int64][]models.Role
+
+func (u fakeUsers) UserByID(_ context.Context, uid int64) (models.User, error) {
+	roles, ok := u[uid]
+	if !ok {
+		return models.User{}, models.ErrUserNotFound
+	}
+	return models.User{UserID: uid, Roles: roles}, nil
+}
+
+var testUsers = fakeUsers{
+	customerID:       {models.RoleCustomer},
+	secondCustomerID: {models.RoleCustomer},
+	supportID:        {models.RoleCustomer, models.RoleSupport},
+	secondSupportID:  {models.RoleSupport},
+	adminID:          {models.RoleAdmin},
+	secondAdminID:    {models.RoleCustomer, models.RoleAdmin},
+	//Роль admin уже снята, но в выданном ранее токене она еще есть
+	demotedAdminID: {models.RoleCustomer},
+}
+
+func TestCanActOn(t *testing.T) {
+	rules := otherUserRoles[authv1.AuthService_LogoutAll_FullMethodName]
+	customer := []models.Role{models.RoleCustomer}
+	support := []models.Role{models.RoleSupport}
+	admin := []models.Role{models.RoleAdmin}
+
+	tests := []struct {
+		name   string
+		caller []models.Role
+		target []models.Role
+		want   bool
+	}{
+		{name: "support on customer", caller: support, target: customer, want: true},
+		{name: "support on user without roles", caller: support, target: nil, want: true},
+		{name: "support on support", caller: support, target: support, want: false},
+		{name: "support on admin", caller: support, target: admin, want: false},
+		{name: "support on customer who is also admin", caller: support, target: []models.Role{models.RoleCustomer, models.RoleAdmin}, want: false},
+		{name: "admin on customer", caller: admin, target: customer, want: true},
+		{name: "admin on support", caller: admin, target: support, want: true},
+		{name: "admin on admin", caller: admin, target: admin, want: true},
+		{name: "admin on every role", caller: admin, target: []models.Role{models.RoleCustomer, models.RoleSupport, models.RoleAdmin}, want: true},
+		{name: "customer on customer", caller: customer, target: customer, want: false},
+		{name: "customer on user without roles", caller: customer, target: nil, want: false},
+		{name: "no roles", caller: nil, target: customer, want: false},
+		{name: "support and customer on customer", caller: []models.Role{models.RoleCustomer, models.RoleSupport}, target: customer, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := canActOn(models.Principal{UserID: 100, Roles: tt.caller}, models.User{UserID: 200, Roles: tt.target}, rules)
+			if got != tt.want {
+				t.Fatalf("canActOn(%v -> %v) = %v, want %v", tt.caller, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+// callRBAC вызывает метод от имени пользователя с ролями из токена и возвращает код ответа
+// и роли, которые увидел обработчик
+func callRBAC(method string, userID int64, tokenRoles []models.Role, req interface{}) (codes.Code, []models.Role) {
+	interceptor := rbacInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)), testUsers)
+	ctx := authctx.WithPrincipal(context.Background(), models.Principal{UserID: userID, Roles: tokenRoles})
+
+	var seen []models.Role
+	_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		principal, _ := authctx.PrincipalFromContext(ctx)
+		seen = principal.Roles
+		return nil, nil
+	})
+	return status.Code(err), seen
+}
+
+func TestRBACLogoutAll(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller int64
+		target int64
+		want   codes.Code
+	}{
+		{name: "customer on self", caller: customerID, target: customerID, want: codes.OK},
+		{name: "customer on own sessions without user_id", caller: customerID, target: 0, want: codes.OK},
+		{name: "customer on another customer", caller: customerID, target: secondCustomerID, want: codes.PermissionDenied},
+		{name: "support on customer", caller: supportID, target: customerID, want: codes.OK},
+		{name: "support on support", caller: supportID, target: secondSupportID, want: codes.PermissionDenied},
+		{name: "support on admin", caller: supportID, target: adminID, want: codes.PermissionDenied},
+		{name: "admin on customer", caller: adminID, target: customerID, want: codes.OK},
+		{name: "admin on support", caller: adminID, target: supportID, want: codes.OK},
+		{name: "admin on admin", caller: adminID, target: secondAdminID, want: codes.OK},
+		{name: "admin on missing user", caller: adminID, target: 999, want: codes.NotFound},
+		{name: "demoted admin with stale token", caller: demotedAdminID, target: customerID, want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Токен всегда несет роли из хранилища, кроме снятой роли у demotedAdminID
+			tokenRoles := testUsers[tt.caller]
+			if tt.caller == demotedAdminID {
+				tokenRoles = []models.Role{models.RoleCustomer, models.RoleAdmin}
+			}
+			code, _ := callRBAC(authv1.AuthService_LogoutAll_FullMethodName, tt.caller, tokenRoles, &authv1.LogoutAllRequest{UserId: tt.target})
+			if code != tt.want {
+				t.Fatalf("code = %v, want %v", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRBACUsesCurrentRoles(t *testing.T) {
+	method := authv1.AuthService_SetUserRoles_FullMethodName
+	req := &authv1.SetUserRolesRequest{UserId: customerID, Roles: []string{string(models.RoleSupport)}}
+
+	if code, seen := callRBAC(method, adminID, []models.Role{models.RoleAdmin}, req); code != codes.OK || !models.HasAnyRole(seen, models.RoleAdmin) {
+		t.Fatalf("admin: code = %v, roles = %v", code, seen)
+	}
+	//Снятая роль перестает действовать до обновления токена
+	if code, _ := callRBAC(method, demotedAdminID, []models.Role{models.RoleAdmin}, req); code != codes.PermissionDenied {
+		t.Fatalf("demoted admin: code = %v, want PermissionDenied", code)
+	}
+	//Выданная роль, наоборот, действует сразу
+	if code, _ := callRBAC(method, secondAdminID, []models.Role{models.RoleCustomer}, req); code != codes.OK {
+		t.Fatalf("promoted admin: code = %v, want OK", code)
+	}
+	if code, _ := callRBAC(method, 999, []models.Role{models.RoleAdmin}, req); code != codes.PermissionDenied {
+		t.Fatalf("deleted user: code = %v, want PermissionDenied", code)
+	}
+}
+
+func TestRBACSkipsUnrestrictedMethods(t *testing.T) {
+	//Для методов без правил хранилище не нужно: вызов без пользователя в контексте проходит
+	interceptor := rbacInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)), fakeUsers{})
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: authv1.AuthService_Logout_FullMethodName},
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("unrestricted method: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package httpapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/http/paymenthttp"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// App - HTTP-сервер для входящих вызовов внешних систем: вебхуки платежного провайдера
+// и, для fake-провайдера, его страница оплаты
+type App struct {
+	logger *slog.Logger
+	server *http.Server
+	port   int
+}
+
+// checkout может быть nil: настоящий провайдер показывает страницу оплаты у себя
+func New(logger *slog.Logger, webhook *paymenthttp.WebhookHandler, checkout http.Handler, port int) *App {
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/payment", webhook)
+	if checkout != nil {
+		mux.Handle("/pay/", checkout)
+	}
+
+	return &App{
+		logger: logger,
+		server: &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      10 * time.Second,
+		},
+		port: port,
+	}
+}
+
+func (a *App) MustStart() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+func (a *App) Run() error {
+	const op = "httpApp.Run"
+	log := a.logger.With(slog.String("operation", op), slog.Int("port", a.port))
+
+	log.Info("starting http server")
+	if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (a *App) Stop(ctx context.Context) {
+	const op = "httpApp.Stop"
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.logger.Error("failed to stop http server", slog.String("operation", op), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,18 @@
+package authctx
+
+import (
+	"context"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(models.Principal)
+	return p, ok
+}
@@ -9,16 +9,98 @@ import (
 )
 
 type Config struct {
-	Env         string     `yaml:"env" env-default:"local"`
-	StoragePath string     `yaml:"storage_path"`
-	GRPC        GRPSconfig `yaml:"grpc"`
+	Env         string            `yaml:"env" env-default:"local"`
+	StoragePath string            `yaml:"storage_path"`
+	GRPC        GRPSconfig        `yaml:"grpc"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	Redis       RedisConfig       `yaml:"redis"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Reservation ReservationConfig `yaml:"reservation"`
+	Payment     PaymentConfig     `yaml:"payment"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
 }
 
+// fakePaymentProvider - имитация оплаты: любой, кто откроет страницу оплаты, может отметить заказ оплаченным
+const fakePaymentProvider = "fake"
+
 type GRPSconfig struct {
 	Port    int           `yaml:"port"`
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type HTTPConfig struct {
+	Port int `yaml:"port" env-default:"8081"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env-default:"localhost:6379"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db"`
+}
+
+type AuthConfig struct {
+	TokenSecret     string        `yaml:"token_secret" env:"AUTH_TOKEN_SECRET"`
+	TokenKeyID      string        `yaml:"token_key_id" env-default:"v1"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env-default:"15m"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	//Пользователи с этими email получают роль admin при регистрации
+	AdminEmails []string `yaml:"admin_emails" env:"AUTH_ADMIN_EMAILS" env-separator:","`
+	//Старые ключи, которые еще принимаются при проверке токенов (kid -> secret)
+	VerificationKeys map[string]string `yaml:"verification_keys"`
+}
+
+type ReservationConfig struct {
+	//Сколько резервация ждет оплаты, прежде чем товар вернется на склад
+	TTL           time.Duration `yaml:"ttl" env-default:"15m"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1m"`
+	SweepBatch    int           `yaml:"sweep_batch" env-default:"100"`
+}
+
+type PaymentConfig struct {
+	//Платежный провайдер, указывается явно; пока есть только fake - локальная имитация без настоящего PSP,
+	//которую можно включить только при env: local
+	Provider string `yaml:"provider" env:"PAYMENT_PROVIDER"`
+	//Адрес страницы оплаты, к которому fake-провайдер добавляет id сессии
+	//Страницу оплаты отдает HTTP-сервер сервиса по пути /pay/
+	FakeBaseURL string `yaml:"fake_base_url" env-default:"http://localhost:8081/pay"`
+	//Куда страница оплаты fake-провайдера отправляет вебхук с итогом оплаты
+	FakeWebhookURL string `yaml:"fake_webhook_url" env-default:"http://localhost:8081/webhooks/payment"`
+	//Общий с провайдером секрет для подписи вебхуков
+	WebhookSecret string `yaml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET"`
+	//Насколько метка времени вебхука может отличаться от текущего времени
+	WebhookTolerance time.Duration `yaml:"webhook_tolerance" env-default:"5m"`
+}
+
+type IdempotencyConfig struct {
+	//Сколько хранится ключ идемпотентности; должно быть больше часовой аренды ключа без ответа
+	TTL             time.Duration `yaml:"ttl" env-default:"24h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+	CleanupBatch    int           `yaml:"cleanup_batch" env-default:"1000"`
+}
+
+type OutboxConfig struct {
+	//Куда публикуются события: log - в лог сервиса, redis - в Redis Stream
+	Publisher    string        `yaml:"publisher" env:"OUTBOX_PUBLISHER" env-default:"log"`
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	Batch        int           `yaml:"batch" env-default:"100"`
+	Stream       string        `yaml:"stream" env-default:"shop:events"`
+	//Примерная длина потока, старые события Redis обрезает
+	StreamMaxLen int64 `yaml:"stream_max_len" env-default:"100000"`
+	//Сколько ждать одну публикацию: пачка идет в одной транзакции, и зависший брокер не должен держать ее долго
+	PublishTimeout time.Duration `yaml:"publish_timeout" env-default:"5s"`
+}
+
+// VerifierKeys возвращает все ключи, которыми можно проверить токен, включая текущий
+func (c AuthConfig) VerifierKeys() map[string]string {
+	keys := make(map[string]string, len(c.VerificationKeys)+1)
+	for kid, secret := range c.VerificationKeys {
+		keys[kid] = secret
+	}
+	keys[c.TokenKeyID] = c.TokenSecret
+	return keys
+}
+
 func MustLoad() *Config {
 	path := getConfigPath()
 	if path == "" {
@@ -32,6 +114,18 @@ func MustLoad() *Config {
 	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
 		panic("failed to read config" + err.Error())
 	}
+	if cfg.Auth.TokenSecret == "" {
+		panic("auth.token_secret is required")
+	}
+	if cfg.Payment.WebhookSecret == "" {
+		panic("payment.webhook_secret is required")
+	}
+	if cfg.Payment.Provider == "" {
+		panic("payment.provider is required")
+	}
+	if cfg.Payment.Provider == fakePaymentProvider && cfg.Env != "local" {
+		panic("payment.provider fake is allowed only with env local, got env " + cfg.Env)
+	}
 	return &cfg
 }
 
@@ -0,0 +1,57 @@
+package config
+
+import "log/slog"
+
+// redacted подставляется в лог вместо секретов: по ним можно подделать токены или вебхуки
+const redacted = "[REDACTED]"
+
+// LogValue - конфиг для лога при старте. Секреты каждая секция скрывает сама
+func (c Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("env", c.Env),
+		//DSN базы содержит пароль
+		slog.String("storage_path", redacted),
+		slog.Any("grpc", c.GRPC),
+		slog.Any("http", c.HTTP),
+		slog.Any("redis", c.Redis),
+		slog.Any("auth", c.Auth),
+		slog.Any("reservation", c.Reservation),
+		slog.Any("payment", c.Payment),
+		slog.Any("outbox", c.Outbox),
+		slog.Any("idempotency", c.Idempotency),
+	)
+}
+
+func (c AuthConfig) LogValue() slog.Value {
+	keyIDs := make([]string, 0, len(c.VerificationKeys))
+	for kid := range c.VerificationKeys {
+		keyIDs = append(keyIDs, kid)
+	}
+	return slog.GroupValue(
+		slog.String("token_secret", redacted),
+		slog.String("token_key_id", c.TokenKeyID),
+		slog.Duration("access_token_ttl", c.AccessTokenTTL),
+		slog.Duration("refresh_token_ttl", c.RefreshTokenTTL),
+		slog.Any("admin_emails", c.AdminEmails),
+		//Только идентификаторы старых ключей, без самих секретов
+		slog.Any("verification_key_ids", keyIDs),
+	)
+}
+
+func (c PaymentConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("provider", c.Provider),
+		slog.String("fake_base_url", c.FakeBaseURL),
+		slog.String("fake_webhook_url", c.FakeWebhookURL),
+		slog.String("webhook_secret", redacted),
+		slog.Duration("webhook_tolerance", c.WebhookTolerance),
+	)
+}
+
+func (c RedisConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("addr", c.Addr),
+		slog.String("password", redacted),
+		slog.Int("db", c.DB),
+	)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "errors"
+
+// CartItem - строка корзины. VariantID заполнен, если товар продается вариантами
+type CartItem struct {
+	ProductID int64
+	VariantID int64
+	SKU       string
+	Quantity  int32
+	Name      string
+	Price     Money
+	Stock     int32
+}
+
+type Cart struct {
+	UserID int64
+	Items  []CartItem
+	Total  Money
+}
+
+// CheckoutResult - либо созданный заказ, либо список строк, которые нельзя зарезервировать
+type CheckoutResult struct {
+	Order    *Order
+	Problems []StockProblem
+}
+
+// MaxCartItemQuantity - сколько штук одной строки можно положить в корзину, даже если на складе больше
+const MaxCartItemQuantity int32 = 1000
+
+var (
+	ErrCartEmpty            = errors.New("cart is empty")
+	ErrCartItemNotFound     = errors.New("cart item not found")
+	ErrCartQuantityExceeded = errors.New("cart quantity exceeds available stock or cart limit")
+)
@@ -0,0 +1,19 @@
+package models
+
+import "errors"
+
+// Category - категория каталога. ParentID равен 0 у корневых категорий
+type Category struct {
+	ID       int64  `db:"category_id"`
+	ParentID int64  `db:"parent_id"`
+	Name     string `db:"name"`
+	Slug     string `db:"slug"`
+}
+
+// CategoryNode - категория с подкатегориями для дерева каталога
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode
+}
+
+var ErrCategoryNotFound = errors.New("category not found")
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Event - доменное событие из outbox. Порядок событий одного агрегата (заказа) сохраняется,
+// доставка - at-least-once, поэтому потребители отбрасывают повторы по ID
+type Event struct {
+	ID            int64
+	AggregateType string
+	AggregateID   int64
+	Type          string
+	Payload       []byte
+	Attempts      int32
+	CreatedAt     time.Time
+}
+
+const AggregateOrder = "order"
+
+const (
+	EventOrderReserved     = "order.reserved"
+	EventOrderPaid         = "order.paid"
+	EventOrderCanceled     = "order.canceled"
+	EventOrderExpired      = "order.expired"
+	EventInventoryReserved = "inventory.reserved"
+	EventInventoryReleased = "inventory.released"
+)
+
+// OrderEvent - тело событий заказа и склада
+type OrderEvent struct {
+	OrderID    int64       `json:"order_id"`
+	UserID     int64       `json:"user_id"`
+	From       OrderStatus `json:"from,omitempty"`
+	Status     OrderStatus `json:"status"`
+	Amount     int64       `json:"amount"`
+	Currency   string      `json:"currency"`
+	Actor      string      `json:"actor,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+	Items      []EventItem `json:"items,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+type EventItem struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id,omitempty"`
+	Quantity  int32 `json:"quantity"`
+}
+
+// ErrEventDeferred - событие не публикуется в этот раз, потому что не доставлено более раннее событие того же заказа
+var ErrEventDeferred = errors.New("event deferred")
@@ -0,0 +1,11 @@
+package models
+
+// IdempotencyRecord - сохраненный результат запроса с ключом идемпотентности.
+// Response == nil, пока первый запрос с этим ключом еще выполняется.
+type IdempotencyRecord struct {
+	UserID      int64
+	Method      string
+	Key         string
+	Fingerprint string
+	Response    []byte
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency - валюта каталога, если у строки в базе она не указана
+const DefaultCurrency = "USD"
+
+// minorUnits - копеек в единице валюты. В базе суммы хранятся как DECIMAL(10,2)
+const minorUnits = 100
+
+// Money - денежная сумма в минимальных единицах валюты (центах, копейках) и ISO-код валюты.
+// Вся арифметика с ценами и суммами заказов идет в целых числах
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid money amount")
+	ErrAmountOverflow   = errors.New("money amount overflow")
+)
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// MoneyFromFloat переводит сумму из устаревших float-полей, округляя до копеек
+func MoneyFromFloat(value float32, currency string) Money {
+	return Money{Amount: int64(math.Round(float64(value) * minorUnits)), Currency: currency}
+}
+
+// ParseMoney разбирает десятичную запись суммы ("1999.99") без потери точности
+func ParseMoney(value, currency string) (Money, error) {
+	amount, err := parseMinorUnits(value)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Mul - сумма строки: цена, умноженная на количество
+func (m Money) Mul(quantity int32) (Money, error) {
+	amount := m.Amount * int64(quantity)
+	if quantity != 0 && (amount/int64(quantity) != m.Amount || (quantity == -1 && m.Amount == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrAmountOverflow, m.Decimal(), quantity)
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Add складывает суммы одной валюты. Нулевое значение Money принимает валюту второго слагаемого
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	amount := m.Amount + other.Amount
+	if (other.Amount > 0 && amount < m.Amount) || (other.Amount < 0 && amount > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrAmountOverflow, m.Decimal(), other.Decimal())
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	amount := m.Amount - other.Amount
+	if (other.Amount > 0 && amount > m.Amount) || (other.Amount < 0 && amount < m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrAmountOverflow, m.Decimal(), other.Decimal())
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) commonCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Float32 - значение для устаревших float-полей API. Для расчетов не использовать
+func (m Money) Float32() float32 {
+	return float32(float64(m.Amount) / minorUnits)
+}
+
+// Decimal - десятичная запись суммы без валюты, как в базе: "1999.99"
+func (m Money) Decimal() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnits, amount%minorUnits)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Scan читает DECIMAL из базы. Валюта хранится в отдельной колонке,
+// поэтому здесь ставится только валюта по умолчанию
+func (m *Money) Scan(src any) error {
+	var amount int64
+	var err error
+	switch v := src.(type) {
+	case []byte:
+		amount, err = parseMinorUnits(string(v))
+	case string:
+		amount, err = parseMinorUnits(v)
+	case int64:
+		if v > math.MaxInt64/minorUnits || v < math.MinInt64/minorUnits {
+			return fmt.Errorf("%w: %d", ErrAmountOverflow, v)
+		}
+		amount = v * minorUnits
+	case float64:
+		amount = int64(math.Round(v * minorUnits))
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+	if err != nil {
+		return err
+	}
+	m.Amount = amount
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	return nil
+}
+
+// Value передает сумму в базу десятичной строкой, чтобы не терять копейки
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+func parseMinorUnits(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || len(fraction) > 2 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil || units > math.MaxInt64/minorUnits-1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	cents, err := strconv.ParseUint(fraction, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	amount := int64(units)*minorUnits + int64(cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
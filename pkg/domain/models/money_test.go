@@ -0,0 +1,231 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr error
+	}{
+		{name: "whole", value: "15", want: 1500},
+		{name: "cents", value: "1999.99", want: 199999},
+		{name: "one fraction digit", value: "0.5", want: 50},
+		{name: "empty fraction", value: "3.", want: 300},
+		{name: "spaces", value: " 12.30 ", want: 1230},
+		{name: "zero", value: "0", want: 0},
+		{name: "negative", value: "-12.34", want: -1234},
+		{name: "negative cents", value: "-0.05", want: -5},
+		//Лишние знаки не округляются молча: сумма с долями копейки отклоняется
+		{name: "three fraction digits", value: "1.005", wantErr: ErrInvalidAmount},
+		{name: "empty", value: "", wantErr: ErrInvalidAmount},
+		{name: "only sign", value: "-", wantErr: ErrInvalidAmount},
+		{name: "no whole part", value: ".50", wantErr: ErrInvalidAmount},
+		{name: "double sign", value: "--1", wantErr: ErrInvalidAmount},
+		{name: "plus sign", value: "+1", wantErr: ErrInvalidAmount},
+		{name: "signed fraction", value: "1.-5", wantErr: ErrInvalidAmount},
+		{name: "letters", value: "12a", wantErr: ErrInvalidAmount},
+		{name: "exponent", value: "1e3", wantErr: ErrInvalidAmount},
+		{name: "largest", value: "92233720368547757.99", want: 9223372036854775799},
+		{name: "too large", value: "92233720368547758.00", wantErr: ErrInvalidAmount},
+		{name: "beyond int64", value: "99999999999999999999", wantErr: ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, "USD")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseMoney(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) error = %v", tt.value, err)
+			}
+			if got.Amount != tt.want || got.Currency != "USD" {
+				t.Fatalf("ParseMoney(%q) = %+v, want %d USD", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyFromFloatRounds(t *testing.T) {
+	tests := []struct {
+		value float32
+		want  int64
+	}{
+		{value: 19.99, want: 1999},
+		{value: 0.1, want: 10},
+		//Половина копейки округляется от нуля
+		{value: 0.125, want: 13},
+		{value: -0.125, want: -13},
+		{value: 0.004, want: 0},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromFloat(tt.value, "USD"); got.Amount != tt.want {
+			t.Errorf("MoneyFromFloat(%v) = %d, want %d", tt.value, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		add     Money
+		sub     Money
+		wantErr error
+	}{
+		{
+			name: "same currency",
+			a:    NewMoney(1500, "USD"), b: NewMoney(250, "USD"),
+			add: NewMoney(1750, "USD"), sub: NewMoney(1250, "USD"),
+		},
+		{
+			name: "zero value takes currency",
+			a:    Money{}, b: NewMoney(250, "EUR"),
+			add: NewMoney(250, "EUR"), sub: NewMoney(-250, "EUR"),
+		},
+		{
+			name: "other without currency",
+			a:    NewMoney(100, "EUR"), b: NewMoney(40, ""),
+			add: NewMoney(140, "EUR"), sub: NewMoney(60, "EUR"),
+		},
+		{
+			name: "currency mismatch",
+			a:    NewMoney(100, "USD"), b: NewMoney(100, "EUR"),
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add error = %v, want %v", err, tt.wantErr)
+			}
+			sub, subErr := tt.a.Sub(tt.b)
+			if !errors.Is(subErr, tt.wantErr) {
+				t.Fatalf("Sub error = %v, want %v", subErr, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if add != tt.add {
+				t.Errorf("Add = %+v, want %+v", add, tt.add)
+			}
+			if sub != tt.sub {
+				t.Errorf("Sub = %+v, want %+v", sub, tt.sub)
+			}
+		})
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	max := NewMoney(math.MaxInt64, "USD")
+	min := NewMoney(math.MinInt64, "USD")
+	one := NewMoney(1, "USD")
+
+	tests := []struct {
+		name    string
+		do      func() (Money, error)
+		want    Money
+		wantErr error
+	}{
+		{name: "add above max", do: func() (Money, error) { return max.Add(one) }, wantErr: ErrAmountOverflow},
+		{name: "add below min", do: func() (Money, error) { return min.Add(NewMoney(-1, "USD")) }, wantErr: ErrAmountOverflow},
+		{name: "add up to max", do: func() (Money, error) { return NewMoney(math.MaxInt64-1, "USD").Add(one) }, want: max},
+		{name: "sub below min", do: func() (Money, error) { return min.Sub(one) }, wantErr: ErrAmountOverflow},
+		{name: "sub negative above max", do: func() (Money, error) { return max.Sub(NewMoney(-1, "USD")) }, wantErr: ErrAmountOverflow},
+		{name: "mul", do: func() (Money, error) { return NewMoney(1999, "USD").Mul(3) }, want: NewMoney(5997, "USD")},
+		{name: "mul by zero", do: func() (Money, error) { return max.Mul(0) }, want: NewMoney(0, "USD")},
+		{name: "mul above max", do: func() (Money, error) { return NewMoney(math.MaxInt64/2+1, "USD").Mul(2) }, wantErr: ErrAmountOverflow},
+		{name: "mul large quantity", do: func() (Money, error) { return NewMoney(math.MaxInt64/1000, "USD").Mul(math.MaxInt32) }, wantErr: ErrAmountOverflow},
+		{name: "mul min by minus one", do: func() (Money, error) { return min.Mul(-1) }, wantErr: ErrAmountOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.do()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: NewMoney(0, "USD"), want: "0.00 USD"},
+		{money: NewMoney(5, "USD"), want: "0.05 USD"},
+		{money: NewMoney(199999, "EUR"), want: "1999.99 EUR"},
+		{money: NewMoney(-1234, "USD"), want: "-12.34 USD"},
+		{money: NewMoney(-5, "USD"), want: "-0.05 USD"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String(%d) = %q, want %q", tt.money.Amount, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyScanValueRoundTrip(t *testing.T) {
+	amounts := []int64{0, 1, 5, 99, 100, 199999, -1, -1234, 9223372036854775799, -9223372036854775799}
+	for _, amount := range amounts {
+		value, err := NewMoney(amount, "EUR").Value()
+		if err != nil {
+			t.Fatalf("Value(%d): %v", amount, err)
+		}
+		//Драйвер Postgres отдает DECIMAL байтами, строкой его передают другие драйверы
+		for _, src := range []any{value, []byte(value.(string))} {
+			got := Money{Currency: "EUR"}
+			if err := got.Scan(src); err != nil {
+				t.Fatalf("Scan(%q): %v", value, err)
+			}
+			if got.Amount != amount || got.Currency != "EUR" {
+				t.Fatalf("Scan(Value(%d)) = %+v", amount, got)
+			}
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    int64
+		wantErr error
+	}{
+		{name: "int64", src: int64(12), want: 1200},
+		{name: "float64 rounds", src: 19.995, want: 2000},
+		{name: "float64 negative", src: -0.015, want: -2},
+		{name: "int64 overflow", src: int64(math.MaxInt64 / 10), wantErr: ErrAmountOverflow},
+		{name: "bad string", src: "abc", wantErr: ErrInvalidAmount},
+		{name: "too many digits", src: []byte("1.234"), wantErr: ErrInvalidAmount},
+		{name: "unsupported type", src: true, wantErr: ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.src)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Scan(%v) error = %v, want %v", tt.src, err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Amount != tt.want || got.Currency != DefaultCurrency {
+				t.Fatalf("Scan(%v) = %+v, want %d %s", tt.src, got, tt.want, DefaultCurrency)
+			}
+		})
+	}
+}
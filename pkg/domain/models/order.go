@@ -6,17 +6,70 @@ import (
 )
 
 type Order struct {
-	ID         int64     `db:"order_id"`
-	UserID     int64     `db:"user_id"`
-	ProductID  int64     `db:"product_id"`
-	Quantity   int32     `db:"quantity"`
-	Sum        float32   `db:"sum"`
-	Status     string    `db:"status"`
-	Time       time.Time `db:"time"`
+	ID         int64       `db:"order_id"`
+	UserID     int64       `db:"user_id"`
+	ProductID  int64       `db:"product_id"`
+	Quantity   int32       `db:"quantity"`
+	Sum        Money       `db:"sum"`
+	Status     OrderStatus `db:"status"`
+	Time       time.Time   `db:"time"`
+	ExpiresAt  time.Time   `db:"expires_at"`
+	Items      []OrderItem
+	Refunds    []Refund
+	Payments   []Payment
+	Returns    []Return
 	PaymentURL string
+	// StatusHistory заполняется только для детального просмотра заказа
+	StatusHistory []OrderStatusChange
+}
+
+// PaidAt - время оплаты по истории статусов, нулевое, если заказ не оплачивался
+func (o Order) PaidAt() time.Time {
+	for _, change := range o.StatusHistory {
+		if change.To == OrderStatusPaid {
+			return change.Time
+		}
+	}
+	return time.Time{}
+}
+
+// RefundedAmount - сколько денег действительно вернул провайдер
+func (o Order) RefundedAmount() Money {
+	return o.refundsAmount(RefundStatusSucceeded)
+}
+
+// PendingRefundAmount - сумма возвратов, которые еще ждут выплаты или выполняются у провайдера
+func (o Order) PendingRefundAmount() Money {
+	return o.refundsAmount(RefundStatusRequested, RefundStatusProcessing)
+}
+
+func (o Order) refundsAmount(statuses ...RefundStatus) Money {
+	sum := Money{Currency: o.Sum.Currency}
+	for _, refund := range o.Refunds {
+		for _, status := range statuses {
+			if refund.Status == status {
+				sum.Amount += refund.Amount.Amount
+			}
+		}
+	}
+	return sum
+}
+
+// OrderItem - строка заказа. Название и цена фиксируются на момент резервации.
+// VariantID и SKU заполнены, если товар продается вариантами.
+type OrderItem struct {
+	ProductID   int64  `db:"product_id"`
+	VariantID   int64  `db:"variant_id"`
+	SKU         string `db:"sku"`
+	ProductName string `db:"product_name"`
+	Quantity    int32  `db:"quantity"`
+	Price       Money  `db:"price"`
+	Sum         Money  `db:"sum"`
 }
 
 var (
 	ErrOrderAlreadyExists = errors.New("order already exists")
 	ErrOrderNotFound      = errors.New("order not found")
+	ErrEmptyOrder         = errors.New("order has no items")
+	ErrOrderNotCancelable = errors.New("order cannot be canceled")
 )
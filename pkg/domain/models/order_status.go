@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OrderStatus - состояние заказа.
+//
+//	reserved -> paid -> shipped -> delivered
+//	reserved -> canceled | expired
+//	paid -> canceled (отмена до отгрузки, деньги возвращаются отдельно)
+//	paid | shipped | delivered -> refunded
+type OrderStatus string
+
+const (
+	OrderStatusReserved  OrderStatus = "reserved"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCanceled  OrderStatus = "canceled"
+	OrderStatusExpired   OrderStatus = "expired"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusReserved:  {OrderStatusPaid, OrderStatusCanceled, OrderStatusExpired},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusCanceled, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered, OrderStatusRefunded},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderStatusReserved, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered,
+		OrderStatusCanceled, OrderStatusExpired, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	for _, next := range orderTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderTransition - смена статуса заказа.
+// Хранилище применяет ее, только если текущий статус все еще равен From.
+type OrderTransition struct {
+	OrderID int64
+	From    OrderStatus
+	To      OrderStatus
+	Actor   string
+	Reason  string
+}
+
+// OrderStatusChange - запись из истории статусов заказа. From пустой у создания заказа.
+type OrderStatusChange struct {
+	From  OrderStatus
+	To    OrderStatus
+	Actor string
+	Time  time.Time
+}
+
+// Инициаторы смены статуса для истории
+const (
+	ActorSystem  = "system"
+	ActorExpirer = "system:expirer"
+	ActorPayment = "payment"
+)
+
+func UserActor(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrInvalidStatus     = errors.New("invalid order status")
+)
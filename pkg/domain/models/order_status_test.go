@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+var allOrderStatuses = []OrderStatus{
+	OrderStatusReserved,
+	OrderStatusPaid,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCanceled,
+	OrderStatusExpired,
+	OrderStatusRefunded,
+}
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	//Таблица выписана отдельно от orderTransitions: каждый переход, которого здесь нет, запрещен
+	allowed := map[OrderStatus]map[OrderStatus]bool{
+		OrderStatusReserved: {
+			OrderStatusPaid:     true,
+			OrderStatusCanceled: true,
+			OrderStatusExpired:  true,
+		},
+		OrderStatusPaid: {
+			OrderStatusShipped:  true,
+			OrderStatusCanceled: true,
+			OrderStatusRefunded: true,
+		},
+		OrderStatusShipped: {
+			OrderStatusDelivered: true,
+			OrderStatusRefunded:  true,
+		},
+		OrderStatusDelivered: {
+			OrderStatusRefunded: true,
+		},
+	}
+
+	for _, from := range allOrderStatuses {
+		for _, to := range allOrderStatuses {
+			want := allowed[from][to]
+			t.Run(fmt.Sprintf("%s->%s", from, to), func(t *testing.T) {
+				if got := from.CanTransitionTo(to); got != want {
+					t.Fatalf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderStatusFinal(t *testing.T) {
+	//Из финальных статусов выхода нет, в том числе expired -> paid после позднего вебхука
+	for _, from := range []OrderStatus{OrderStatusCanceled, OrderStatusExpired, OrderStatusRefunded} {
+		for _, to := range allOrderStatuses {
+			if from.CanTransitionTo(to) {
+				t.Errorf("%s.CanTransitionTo(%s) = true, want final status", from, to)
+			}
+		}
+	}
+}
+
+func TestOrderStatusUnknown(t *testing.T) {
+	unknown := OrderStatus("lost")
+	if unknown.Valid() {
+		t.Fatalf("%q.Valid() = true", unknown)
+	}
+	for _, status := range allOrderStatuses {
+		if !status.Valid() {
+			t.Errorf("%q.Valid() = false", status)
+		}
+		if status.CanTransitionTo(unknown) || unknown.CanTransitionTo(status) {
+			t.Errorf("transition between %q and unknown status is allowed", status)
+		}
+	}
+}
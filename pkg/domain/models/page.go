@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultPageSize int32 = 20
+	MaxPageSize     int32 = 100
+)
+
+// OrderCursor - позиция в истории заказов: последний отданный заказ страницы.
+// Заказы идут по (time, order_id) по убыванию
+type OrderCursor struct {
+	Time time.Time `json:"t"`
+	ID   int64     `json:"id"`
+}
+
+// OrderHistoryFilter - условия выборки истории заказов. Нулевые поля не фильтруют
+type OrderHistoryFilter struct {
+	UserID    int64
+	Statuses  []OrderStatus
+	From      time.Time // включительно
+	To        time.Time // не включительно
+	ProductID int64
+	After     *OrderCursor
+	Limit     int32
+}
+
+type OrderPage struct {
+	Orders        []Order
+	NextPageToken string
+}
+
+// ProductCursor - последний товар отданной страницы каталога.
+// Value - значение колонки сортировки, курсор годится только для той же сортировки
+type ProductCursor struct {
+	Sort  ProductSort `json:"s,omitempty"`
+	Value string      `json:"v,omitempty"`
+	ID    int64       `json:"id"`
+}
+
+// ProductQuery - выборка каталога. After (keyset) и Offset взаимоисключающие,
+// Offset оставлен для старых клиентов
+type ProductQuery struct {
+	Filter    ProductFilter
+	Sort      ProductSort
+	Limit     int32
+	Offset    int32
+	After     *ProductCursor
+	WithTotal bool
+}
+
+type ProductPage struct {
+	Products      []Product
+	NextPageToken string
+	// TotalCount заполняется, только если запрошен ProductQuery.WithTotal
+	TotalCount int64
+}
+
+var ErrInvalidPageToken = errors.New("invalid page token")
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
+)
+
+// PaymentSession - платежная сессия у провайдера: по URL покупатель оплачивает заказ
+type PaymentSession struct {
+	Provider   string
+	ExternalID string
+	OrderID    int64
+	URL        string
+	Amount     Money
+	Status     PaymentStatus
+	ExpiresAt  time.Time
+	//Ответ провайдера как есть, сохраняется для разбора спорных платежей
+	RawPayload []byte
+}
+
+// Payment - запись о платеже заказа. Attempts - сколько раз провайдер сообщал о попытке оплаты
+type Payment struct {
+	ID         int64         `db:"id"`
+	OrderID    int64         `db:"order_id"`
+	Provider   string        `db:"provider"`
+	ExternalID string        `db:"external_id"`
+	URL        string        `db:"payment_url"`
+	Amount     Money         `db:"amount"`
+	Status     PaymentStatus `db:"status"`
+	Attempts   int32         `db:"attempts"`
+	RawPayload []byte        `db:"raw_payload"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+// PaymentResult - итог попытки оплаты от провайдера или от администратора.
+// Без ExternalID относится к последнему платежу заказа
+type PaymentResult struct {
+	OrderID    int64
+	ExternalID string
+	Success    bool
+	RawPayload []byte
+}
+
+func (r PaymentResult) Status() PaymentStatus {
+	if r.Success {
+		return PaymentStatusSucceeded
+	}
+	return PaymentStatusFailed
+}
+
+// ProviderRefund - ответ провайдера на возврат денег
+type ProviderRefund struct {
+	ExternalID string
+	Amount     Money
+	Status     RefundStatus
+}
+
+var (
+	ErrPaymentNotFound     = errors.New("payment not found")
+	ErrPaymentUnavailable  = errors.New("payment provider unavailable")
+	ErrRefundExceedsAmount = errors.New("refund exceeds paid amount")
+)
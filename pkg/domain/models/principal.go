@@ -0,0 +1,18 @@
+package models
+
+// Principal - аутентифицированный пользователь, от имени которого выполняется запрос
+type Principal struct {
+	UserID    int64
+	Email     string
+	SessionID string
+	Roles     []Role
+}
+
+func (p Principal) HasAnyRole(roles ...Role) bool {
+	return HasAnyRole(p.Roles, roles...)
+}
+
+// IsStaff - сотрудники магазина (поддержка и администраторы)
+func (p Principal) IsStaff() bool {
+	return p.HasAnyRole(RoleSupport, RoleAdmin)
+}
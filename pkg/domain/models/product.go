@@ -1,15 +1,122 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 type Product struct {
-	ProductID int64   `db:"product_id"`
-	Name      string  `db:"name"`
-	Price     float32 `db:"price"`
-	Stock     int32   `db:"stock"`
+	ProductID int64  `db:"product_id"`
+	Name      string `db:"name"`
+	Price     Money  `db:"price"`
+	Stock     int32  `db:"stock"`
+	// Variants заполняется при просмотре одного товара. Если варианты есть,
+	// заказывать можно только их, а Stock - сумма остатков вариантов
+	Variants []ProductVariant `db:"-"`
+}
+
+// ProductVariant - вариант товара со своим SKU и остатком.
+// Price - итоговая цена: своя цена варианта или цена товара
+type ProductVariant struct {
+	ID         int64             `db:"variant_id"`
+	ProductID  int64             `db:"product_id"`
+	SKU        string            `db:"sku"`
+	Attributes map[string]string `db:"-"`
+	Price      Money             `db:"price"`
+	Stock      int32             `db:"stock"`
+}
+
+// Variant ищет вариант товара по id
+func (p Product) Variant(variantID int64) (ProductVariant, bool) {
+	for _, variant := range p.Variants {
+		if variant.ID == variantID {
+			return variant, true
+		}
+	}
+	return ProductVariant{}, false
+}
+
+// ProductUpdate - частичное обновление товара, nil-поля не меняются
+type ProductUpdate struct {
+	Name  *string
+	Price *Money
+	Stock *int32
+}
+
+// ProductSearchHit - товар из результатов поиска.
+// Snippet - название с найденными словами в <b></b>, Fuzzy - найден по похожести, а не по словам
+type ProductSearchHit struct {
+	Product
+	Rank    float32 `db:"rank"`
+	Snippet string  `db:"snippet"`
+	Fuzzy   bool
+}
+
+// ProductFilter - фильтр каталога, нулевые поля не фильтруют
+type ProductFilter struct {
+	MinPrice     *Money
+	MaxPrice     *Money
+	InStockOnly  bool
+	NameContains string
+	// CategoryID - товары категории и всех ее подкатегорий
+	CategoryID int64
+}
+
+// ProductSort - порядок каталога. По умолчанию товары идут по product_id
+type ProductSort string
+
+const (
+	ProductSortDefault   ProductSort = ""
+	ProductSortPriceAsc  ProductSort = "price_asc"
+	ProductSortPriceDesc ProductSort = "price_desc"
+	ProductSortNameAsc   ProductSort = "name_asc"
+	ProductSortNameDesc  ProductSort = "name_desc"
+	ProductSortNewest    ProductSort = "newest"
+)
+
+func (s ProductSort) Valid() bool {
+	switch s {
+	case ProductSortDefault, ProductSortPriceAsc, ProductSortPriceDesc, ProductSortNameAsc, ProductSortNameDesc, ProductSortNewest:
+		return true
+	}
+	return false
 }
 
 var (
 	ErrProductNotFound = errors.New("product not found")
 	ErrNotEnoughStock  = errors.New("not enough stock")
+	ErrProductInUse    = errors.New("product is referenced by orders")
+	ErrEmptySearch     = errors.New("search query is empty")
+	ErrVariantNotFound = errors.New("product variant not found")
+	ErrVariantRequired = errors.New("product is sold by variants, variant_id is required")
 )
+
+// StockProblem - строка заказа, которую нельзя зарезервировать.
+// Reason - ErrProductNotFound или ErrNotEnoughStock.
+type StockProblem struct {
+	ProductID int64
+	VariantID int64
+	Requested int32
+	Available int32
+	Reason    error
+}
+
+// StockError возвращается резервацией со списком всех проблемных строк
+type StockError struct {
+	Problems []StockProblem
+}
+
+func (e *StockError) Error() string {
+	return fmt.Sprintf("cannot reserve %d order line(s)", len(e.Problems))
+}
+
+// Is позволяет проверять StockError через errors.Is(err, ErrNotEnoughStock),
+// errors.Is(err, ErrProductNotFound) и т.д.
+func (e *StockError) Is(target error) bool {
+	for _, p := range e.Problems {
+		if p.Reason == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// RefundStatus - состояние возврата денег.
+//
+//	requested -> processing -> succeeded | failed
+//
+// requested - возврат ждет выплаты (например, после отмены оплаченного заказа),
+// processing - отправлен провайдеру. Неудачная выплата requested-возврата возвращает его в requested
+type RefundStatus string
+
+const (
+	RefundStatusRequested  RefundStatus = "requested"
+	RefundStatusProcessing RefundStatus = "processing"
+	RefundStatusSucceeded  RefundStatus = "succeeded"
+	RefundStatusFailed     RefundStatus = "failed"
+)
+
+var ErrRefundNotFound = errors.New("refund not found")
+
+type Refund struct {
+	ID      int64        `db:"id"`
+	OrderID int64        `db:"order_id"`
+	Amount  Money        `db:"amount"`
+	Status  RefundStatus `db:"status"`
+	Reason  string       `db:"reason"`
+	//ReturnID заполнен, если деньги возвращаются по заявке на возврат товара
+	ReturnID   int64     `db:"return_id"`
+	ExternalID string    `db:"external_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// RefundResult - выполненный возврат денег и заказ после него
+type RefundResult struct {
+	Refund Refund
+	Order  Order
+}
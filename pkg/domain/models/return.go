@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ReturnStatus - состояние заявки на возврат товара.
+//
+//	requested -> approved -> refunded
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+)
+
+// Return - заявка покупателя на возврат части оплаченного заказа.
+// Amount - стоимость возвращаемых строк по ценам заказа
+type Return struct {
+	ID        int64
+	OrderID   int64
+	Status    ReturnStatus
+	Reason    string
+	Restocked bool
+	Items     []ReturnItem
+	Amount    Money
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ReturnItem struct {
+	ProductID int64
+	VariantID int64
+	Quantity  int32
+}
+
+var (
+	ErrReturnNotFound      = errors.New("return not found")
+	ErrOrderNotReturnable  = errors.New("order cannot be returned")
+	ErrReturnExceedsOrder  = errors.New("return quantity exceeds ordered quantity")
+	ErrReturnNotApprovable = errors.New("return is not awaiting approval")
+	ErrReturnNotApproved   = errors.New("return is not approved")
+	ErrReturnRefunding     = errors.New("return already has a refund")
+	ErrRefundExceedsReturn = errors.New("refund exceeds return amount")
+	ErrNothingToRefund     = errors.New("nothing to refund")
+)
+
+// returnableStatuses - вернуть можно только оплаченный заказ, в том числе уже доставленный
+var returnableStatuses = map[OrderStatus]bool{
+	OrderStatusPaid:      true,
+	OrderStatusShipped:   true,
+	OrderStatusDelivered: true,
+}
+
+func (s OrderStatus) Returnable() bool {
+	return returnableStatuses[s]
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Session - один вход пользователя. Все refresh-токены, полученные ротацией
+// из одного логина, принадлежат одной сессии (семейству токенов).
+type Session struct {
+	ID        string
+	UserID    int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshToken - запись о refresh-токене в хранилище.
+// Сам токен не хранится, только его хэш.
+type RefreshToken struct {
+	Hash      string
+	UserID    int64
+	SessionID string
+	ExpiresAt time.Time
+}
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used")
+	ErrSessionNotFound      = errors.New("session not found")
+)
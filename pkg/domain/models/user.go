@@ -6,9 +6,39 @@ type User struct {
 	UserID   int64
 	Email    string
 	Passhash []byte
+	Roles    []Role
+}
+
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleSupport  Role = "support"
+	RoleAdmin    Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleCustomer, RoleSupport, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// HasAnyRole проверяет, есть ли среди ролей хотя бы одна из нужных
+func HasAnyRole(roles []Role, wanted ...Role) bool {
+	for _, r := range roles {
+		for _, w := range wanted {
+			if r == w {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user already exists")
+	ErrInvalidRole  = errors.New("invalid role")
 )
@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 	authv1 "github.com/kavshevnova/product-reservation-system/gen/go/auth"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
 	"github.com/kavshevnova/product-reservation-system/pkg/services/auth"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type Auth interface {
 	RegisterNewUser(ctx context.Context, email, password string) (userID int64, err error)
-	LoginUser(ctx context.Context, email, password string) (success bool, err error)
+	LoginUser(ctx context.Context, email, password string) (models.Tokens, error)
+	Refresh(ctx context.Context, refreshToken string) (models.Tokens, error)
+	Logout(ctx context.Context, sessionID string) error
+	LogoutAll(ctx context.Context, userID int64) (int, error)
+	SetUserRoles(ctx context.Context, userID int64, roles []models.Role) error
 }
 
 type AuthServerAPI struct {
@@ -44,14 +50,90 @@ func (a *AuthServerAPI) Login(ctx context.Context, request *authv1.LoginRequest)
 	if err := ValidateLogin(request); err != nil {
 		return nil, err
 	}
-	success, err := a.auth.LoginUser(ctx, request.GetEmail(), request.GetPassword())
+	tokens, err := a.auth.LoginUser(ctx, request.GetEmail(), request.GetPassword())
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid credentials")
 		}
 		return nil, status.Error(codes.Internal, "internal server error")
 	}
-	return &authv1.LoginResponse{Success: success}, nil
+	return &authv1.LoginResponse{
+		Success:               true,
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt.Unix(),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt.Unix(),
+	}, nil
+}
+
+func (a *AuthServerAPI) Refresh(ctx context.Context, request *authv1.RefreshRequest) (*authv1.RefreshResponse, error) {
+	if request.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing refresh token")
+	}
+	tokens, err := a.auth.Refresh(ctx, request.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return &authv1.RefreshResponse{
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt.Unix(),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt.Unix(),
+	}, nil
+}
+
+func (a *AuthServerAPI) Logout(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	principal, ok := authctx.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if err := a.auth.Logout(ctx, principal.SessionID); err != nil {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *AuthServerAPI) LogoutAll(ctx context.Context, request *authv1.LogoutAllRequest) (*authv1.LogoutAllResponse, error) {
+	principal, ok := authctx.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	//Права на чужие сессии проверяет rbacInterceptor по таблице otherUserRoles
+	userID := request.GetUserId()
+	if userID == 0 {
+		userID = principal.UserID
+	}
+	revoked, err := a.auth.LogoutAll(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return &authv1.LogoutAllResponse{RevokedSessions: int32(revoked)}, nil
+}
+
+func (a *AuthServerAPI) SetUserRoles(ctx context.Context, request *authv1.SetUserRolesRequest) (*emptypb.Empty, error) {
+	if request.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if len(request.GetRoles()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one role is required")
+	}
+	roles := make([]models.Role, 0, len(request.GetRoles()))
+	for _, r := range request.GetRoles() {
+		roles = append(roles, models.Role(r))
+	}
+	if err := a.auth.SetUserRoles(ctx, request.GetUserId(), roles); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidRole):
+			return nil, status.Error(codes.InvalidArgument, "invalid role")
+		case errors.Is(err, models.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return &emptypb.Empty{}, nil
 }
 
 func (a *AuthServerAPI) mustEmbedUnimplementedAuthServiceServer() {}
@@ -0,0 +1,227 @@
+package cartgrpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	cartv1 "github.com/kavshevnova/product-reservation-system/gen/go/cart"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/idempotencygrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Cart interface {
+	AddItem(ctx context.Context, userID, productID, variantID int64, quantity int32) (*models.Cart, error)
+	UpdateQuantity(ctx context.Context, userID, productID, variantID int64, quantity int32) (*models.Cart, error)
+	RemoveItem(ctx context.Context, userID, productID, variantID int64) (*models.Cart, error)
+	GetCart(ctx context.Context, userID int64) (*models.Cart, error)
+	Clear(ctx context.Context, userID int64) error
+	Checkout(ctx context.Context, userID int64) (*models.CheckoutResult, error)
+}
+
+type CartServerAPI struct {
+	cartv1.UnimplementedCartServiceServer
+	log         *slog.Logger
+	cart        Cart
+	idempotency idempotencygrpc.Store
+}
+
+func RegisterCartServerAPI(grpcServer *grpc.Server, log *slog.Logger, cart Cart, idempotency idempotencygrpc.Store) {
+	cartv1.RegisterCartServiceServer(grpcServer, &CartServerAPI{log: log, cart: cart, idempotency: idempotency})
+}
+
+func (c *CartServerAPI) AddItem(ctx context.Context, req *cartv1.AddItemRequest) (*cartv1.Cart, error) {
+	if req.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if req.GetVariantId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "variant_id cannot be negative")
+	}
+	if req.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := c.cart.AddItem(ctx, userID, req.GetProductId(), req.GetVariantId(), req.GetQuantity())
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			return nil, status.Error(codes.NotFound, "product not found")
+		case errors.Is(err, models.ErrVariantNotFound):
+			return nil, status.Error(codes.NotFound, "variant not found")
+		case errors.Is(err, models.ErrVariantRequired):
+			return nil, status.Error(codes.InvalidArgument, "variant_id is required for this product")
+		case errors.Is(err, models.ErrCartQuantityExceeded):
+			return nil, status.Error(codes.FailedPrecondition, "quantity exceeds available stock or cart limit")
+		}
+		return nil, status.Error(codes.Internal, "failed to add item")
+	}
+	return toCartProto(cart), nil
+}
+
+func (c *CartServerAPI) UpdateQuantity(ctx context.Context, req *cartv1.UpdateQuantityRequest) (*cartv1.Cart, error) {
+	if req.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if req.GetVariantId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "variant_id cannot be negative")
+	}
+	if req.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity cannot be negative")
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := c.cart.UpdateQuantity(ctx, userID, req.GetProductId(), req.GetVariantId(), req.GetQuantity())
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrCartItemNotFound):
+			return nil, status.Error(codes.NotFound, "item not in cart")
+		case errors.Is(err, models.ErrCartQuantityExceeded):
+			return nil, status.Error(codes.FailedPrecondition, "quantity exceeds available stock or cart limit")
+		}
+		return nil, status.Error(codes.Internal, "failed to update quantity")
+	}
+	return toCartProto(cart), nil
+}
+
+func (c *CartServerAPI) RemoveItem(ctx context.Context, req *cartv1.RemoveItemRequest) (*cartv1.Cart, error) {
+	if req.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if req.GetVariantId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "variant_id cannot be negative")
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := c.cart.RemoveItem(ctx, userID, req.GetProductId(), req.GetVariantId())
+	if err != nil {
+		if errors.Is(err, models.ErrCartItemNotFound) {
+			return nil, status.Error(codes.NotFound, "item not in cart")
+		}
+		return nil, status.Error(codes.Internal, "failed to remove item")
+	}
+	return toCartProto(cart), nil
+}
+
+func (c *CartServerAPI) GetCart(ctx context.Context, _ *emptypb.Empty) (*cartv1.Cart, error) {
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := c.cart.GetCart(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get cart")
+	}
+	return toCartProto(cart), nil
+}
+
+func (c *CartServerAPI) Clear(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cart.Clear(ctx, userID); err != nil {
+		return nil, status.Error(codes.Internal, "failed to clear cart")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Checkout с ключом идемпотентности создает не больше одного заказа:
+// повтор после обрыва связи вернет тот же заказ и ту же ссылку на оплату
+func (c *CartServerAPI) Checkout(ctx context.Context, req *cartv1.CheckoutRequest) (*cartv1.CheckoutResponse, error) {
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return idempotencygrpc.Do(ctx, c.log, c.idempotency, cartv1.CartService_Checkout_FullMethodName, req.GetIdempotencyKey(), req,
+		func() (*cartv1.CheckoutResponse, error) {
+			return c.checkout(ctx, userID)
+		})
+}
+
+func (c *CartServerAPI) checkout(ctx context.Context, userID int64) (*cartv1.CheckoutResponse, error) {
+	result, err := c.cart.Checkout(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrCartEmpty) {
+			return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+		}
+		if errors.Is(err, models.ErrPaymentUnavailable) {
+			return nil, status.Error(codes.Unavailable, "payment provider unavailable")
+		}
+		return nil, status.Error(codes.Internal, "failed to checkout")
+	}
+	if len(result.Problems) > 0 {
+		problems := make([]*cartv1.StockProblem, 0, len(result.Problems))
+		for _, p := range result.Problems {
+			problems = append(problems, &cartv1.StockProblem{
+				ProductId: p.ProductID,
+				VariantId: p.VariantID,
+				Requested: p.Requested,
+				Available: p.Available,
+				Reason:    stockProblemReason(p.Reason),
+			})
+		}
+		return &cartv1.CheckoutResponse{Status: "Not enough stock", Problems: problems}, nil
+	}
+	return &cartv1.CheckoutResponse{
+		OrderId:    result.Order.ID,
+		Status:     string(result.Order.Status),
+		PaymentURL: result.Order.PaymentURL,
+		Sum:        result.Order.Sum.Float32(),
+		SumMoney:   toMoneyProto(result.Order.Sum),
+	}, nil
+}
+
+func (c *CartServerAPI) mustEmbedUnimplementedCartServiceServer() {}
+
+func currentUser(ctx context.Context) (int64, error) {
+	principal, ok := authctx.PrincipalFromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return principal.UserID, nil
+}
+
+func stockProblemReason(reason error) string {
+	switch {
+	case errors.Is(reason, models.ErrProductNotFound):
+		return "not_found"
+	case errors.Is(reason, models.ErrVariantNotFound):
+		return "variant_not_found"
+	case errors.Is(reason, models.ErrVariantRequired):
+		return "variant_required"
+	}
+	return "not_enough_stock"
+}
+
+func toCartProto(cart *models.Cart) *cartv1.Cart {
+	items := make([]*cartv1.CartItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, &cartv1.CartItem{
+			ProductId:  item.ProductID,
+			VariantId:  item.VariantID,
+			Sku:        item.SKU,
+			Quantity:   item.Quantity,
+			Name:       item.Name,
+			Price:      item.Price.Float32(),
+			PriceMoney: toMoneyProto(item.Price),
+			Stock:      item.Stock,
+		})
+	}
+	return &cartv1.Cart{Items: items, Total: cart.Total.Float32(), TotalMoney: toMoneyProto(cart.Total)}
+}
+
+func toMoneyProto(money models.Money) *cartv1.Money {
+	return &cartv1.Money{Amount: money.Amount, Currency: money.Currency}
+}
@@ -0,0 +1,151 @@
+package cartgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	cartv1 "github.com/kavshevnova/product-reservation-system/gen/go/cart"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/idempotencygrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// countingCart создает новый заказ на каждый Checkout
+type countingCart struct {
+	Cart
+	mu     sync.Mutex
+	orders int64
+}
+
+func (c *countingCart) Checkout(_ context.Context, _ int64) (*models.CheckoutResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.orders++
+	return &models.CheckoutResult{Order: &models.Order{
+		ID:         c.orders,
+		Status:     models.OrderStatusReserved,
+		PaymentURL: fmt.Sprintf("https://pay.example/%d", c.orders),
+		Sum:        models.NewMoney(1500, "USD"),
+	}}, nil
+}
+
+type memoryIdempotency struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func recordKey(userID int64, method, key string) string {
+	return fmt.Sprintf("%d/%s/%s", userID, method, key)
+}
+
+func (m *memoryIdempotency) StartIdempotentRequest(_ context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := recordKey(record.UserID, record.Method, record.Key)
+	if existing, ok := m.records[k]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	m.records[k] = &record
+	return nil, nil
+}
+
+func (m *memoryIdempotency) SaveIdempotentResponse(_ context.Context, userID int64, method, key string, response []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[recordKey(userID, method, key)].Response = response
+	return nil
+}
+
+func (m *memoryIdempotency) ReleaseIdempotencyKey(_ context.Context, userID int64, method, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, recordKey(userID, method, key))
+	return nil
+}
+
+func newTestServer() (*CartServerAPI, *countingCart) {
+	cart := &countingCart{}
+	return &CartServerAPI{
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cart:        cart,
+		idempotency: &memoryIdempotency{records: make(map[string]*models.IdempotencyRecord)},
+	}, cart
+}
+
+func userContext(userID int64) context.Context {
+	return authctx.WithPrincipal(context.Background(), models.Principal{UserID: userID, Roles: []models.Role{models.RoleCustomer}})
+}
+
+func TestCheckoutIdempotencyKey(t *testing.T) {
+	server, cart := newTestServer()
+	ctx := userContext(1)
+	req := &cartv1.CheckoutRequest{IdempotencyKey: "checkout-1"}
+
+	first, err := server.Checkout(ctx, req)
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	retry, err := server.Checkout(ctx, req)
+	if err != nil {
+		t.Fatalf("retry Checkout: %v", err)
+	}
+	if cart.orders != 1 {
+		t.Fatalf("retry created %d orders, want 1", cart.orders)
+	}
+	if retry.GetOrderId() != first.GetOrderId() || retry.GetPaymentURL() != first.GetPaymentURL() {
+		t.Fatalf("retry response = %v, want %v", retry, first)
+	}
+
+	//Ключ из метаданных работает так же, как поле запроса
+	mdCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(idempotencygrpc.KeyHeader, "checkout-2"))
+	second, err := server.Checkout(mdCtx, &cartv1.CheckoutRequest{})
+	if err != nil {
+		t.Fatalf("Checkout with metadata key: %v", err)
+	}
+	again, err := server.Checkout(mdCtx, &cartv1.CheckoutRequest{})
+	if err != nil {
+		t.Fatalf("retry Checkout with metadata key: %v", err)
+	}
+	if cart.orders != 2 || again.GetOrderId() != second.GetOrderId() {
+		t.Fatalf("orders = %d, retry order = %d, want 2 and %d", cart.orders, again.GetOrderId(), second.GetOrderId())
+	}
+}
+
+func TestCheckoutKeysArePerUser(t *testing.T) {
+	server, cart := newTestServer()
+	req := &cartv1.CheckoutRequest{IdempotencyKey: "same-key"}
+
+	if _, err := server.Checkout(userContext(1), req); err != nil {
+		t.Fatalf("Checkout user 1: %v", err)
+	}
+	if _, err := server.Checkout(userContext(2), req); err != nil {
+		t.Fatalf("Checkout user 2: %v", err)
+	}
+	if cart.orders != 2 {
+		t.Fatalf("orders = %d, want 2", cart.orders)
+	}
+}
+
+func TestCheckoutWithoutKey(t *testing.T) {
+	server, cart := newTestServer()
+
+	for i := 0; i < 2; i++ {
+		if _, err := server.Checkout(userContext(1), &cartv1.CheckoutRequest{}); err != nil {
+			t.Fatalf("Checkout: %v", err)
+		}
+	}
+	if cart.orders != 2 {
+		t.Fatalf("orders = %d, want 2", cart.orders)
+	}
+	if _, err := server.Checkout(context.Background(), &cartv1.CheckoutRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous Checkout error = %v, want Unauthenticated", err)
+	}
+}
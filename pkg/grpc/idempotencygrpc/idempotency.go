@@ -0,0 +1,141 @@
+// Package idempotencygrpc - ключи идемпотентности для gRPC-методов, которые создают заказы и двигают деньги
+package idempotencygrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// KeyHeader - метаданные с ключом идемпотентности для запросов без поля idempotency_key
+const KeyHeader = "idempotency-key"
+
+type Store interface {
+	StartIdempotentRequest(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	SaveIdempotentResponse(ctx context.Context, userID int64, method, key string, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, userID int64, method, key string) error
+}
+
+// Do выполняет call не больше одного раза для ключа идемпотентности.
+// Повтор с тем же ключом и тем же запросом возвращает сохраненный ответ,
+// с тем же ключом и другим запросом - FailedPrecondition.
+// Без ключа call просто выполняется.
+func Do[T proto.Message](
+	ctx context.Context,
+	log *slog.Logger,
+	store Store,
+	method string,
+	requestKey string,
+	req proto.Message,
+	call func() (T, error),
+) (T, error) {
+	var zero T
+
+	key := idempotencyKey(ctx, requestKey)
+	if key == "" {
+		return call()
+	}
+	if len(key) > 255 {
+		return zero, status.Error(codes.InvalidArgument, "idempotency key is too long")
+	}
+
+	//Ключи одного пользователя не пересекаются с ключами другого
+	var userID int64
+	if principal, ok := authctx.PrincipalFromContext(ctx); ok {
+		userID = principal.UserID
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return zero, status.Error(codes.Internal, "internal server error")
+	}
+
+	existing, err := store.StartIdempotentRequest(ctx, models.IdempotencyRecord{
+		UserID:      userID,
+		Method:      method,
+		Key:         key,
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return zero, status.Error(codes.Internal, "internal server error")
+	}
+	if existing != nil {
+		if existing.Fingerprint != fingerprint {
+			return zero, status.Error(codes.FailedPrecondition, "idempotency key was already used with a different request")
+		}
+		if existing.Response == nil {
+			return zero, status.Error(codes.Aborted, "request with this idempotency key is still in progress")
+		}
+		resp := zero.ProtoReflect().New().Interface().(T)
+		if err := proto.Unmarshal(existing.Response, resp); err != nil {
+			return zero, status.Error(codes.Internal, "internal server error")
+		}
+		return resp, nil
+	}
+
+	log = log.With(
+		slog.String("operation", "idempotencygrpc.Do"),
+		slog.String("method", method),
+		slog.Int64("user_id", userID),
+		slog.String("idempotency_key", key),
+	)
+	//Клиент мог отключиться, пока выполнялся call, но ключ все равно нужно освободить или закрыть ответом:
+	//иначе он останется занятым без ответа до истечения аренды
+	storeCtx := context.WithoutCancel(ctx)
+
+	resp, err := call()
+	if err != nil {
+		//Запрос не выполнился - освобождаем ключ, чтобы его можно было повторить
+		if releaseErr := store.ReleaseIdempotencyKey(storeCtx, userID, method, key); releaseErr != nil {
+			log.Error("Failed to release idempotency key", slog.String("error", releaseErr.Error()))
+		}
+		return zero, err
+	}
+
+	//Операция уже выполнена, поэтому ответ возвращаем, даже если не смогли его сохранить
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		log.Error("Failed to marshal idempotent response", slog.String("error", err.Error()))
+		return resp, nil
+	}
+	if err := store.SaveIdempotentResponse(storeCtx, userID, method, key, data); err != nil {
+		log.Error("Failed to save idempotent response", slog.String("error", err.Error()))
+	}
+	return resp, nil
+}
+
+// idempotencyKey берет ключ из поля запроса, а если его нет - из метаданных
+func idempotencyKey(ctx context.Context, requestKey string) string {
+	if requestKey != "" {
+		return requestKey
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(KeyHeader); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// requestFingerprint - хэш запроса без самого ключа идемпотентности
+func requestFingerprint(req proto.Message) (string, error) {
+	clone := proto.Clone(req)
+	msg := clone.ProtoReflect()
+	if fd := msg.Descriptor().Fields().ByName("idempotency_key"); fd != nil {
+		msg.Clear(fd)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(clone)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,63 @@
+package shopgrpc
+
+import (
+	shopv1 "github.com/kavshevnova/product-reservation-system/gen/go/shop"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toMoneyProto(money models.Money) *shopv1.Money {
+	return &shopv1.Money{Amount: money.Amount, Currency: money.Currency}
+}
+
+// moneyFromRequest берет сумму из Money-поля, а для старых клиентов - из float-поля
+func moneyFromRequest(money *shopv1.Money, legacy float32) (models.Money, error) {
+	if money == nil {
+		return models.MoneyFromFloat(legacy, models.DefaultCurrency), nil
+	}
+	currency := money.GetCurrency()
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	if !validCurrency(currency) {
+		return models.Money{}, status.Errorf(codes.InvalidArgument, "invalid currency %q", money.GetCurrency())
+	}
+	return models.NewMoney(money.GetAmount(), currency), nil
+}
+
+// validCurrency - код ISO 4217: три заглавные латинские буквы
+func validCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// priceFilterFromRequest собирает границы цены фильтра; nil - граница не задана
+func priceFilterFromRequest(filter *shopv1.ProductFilter) (*models.Money, *models.Money, error) {
+	var minPrice, maxPrice *models.Money
+	if filter == nil {
+		return nil, nil, nil
+	}
+	if filter.GetMinPriceMoney() != nil || filter.MinPrice != nil {
+		price, err := moneyFromRequest(filter.GetMinPriceMoney(), filter.GetMinPrice())
+		if err != nil {
+			return nil, nil, err
+		}
+		minPrice = &price
+	}
+	if filter.GetMaxPriceMoney() != nil || filter.MaxPrice != nil {
+		price, err := moneyFromRequest(filter.GetMaxPriceMoney(), filter.GetMaxPrice())
+		if err != nil {
+			return nil, nil, err
+		}
+		maxPrice = &price
+	}
+	return minPrice, maxPrice, nil
+}
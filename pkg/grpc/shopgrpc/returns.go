@@ -0,0 +1,164 @@
+package shopgrpc
+
+import (
+	"context"
+	"errors"
+
+	shopv1 "github.com/kavshevnova/product-reservation-system/gen/go/shop"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/idempotencygrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ShopServerAPI) RequestReturn(ctx context.Context, req *shopv1.RequestReturnRequest) (*shopv1.Return, error) {
+	if err := ValidateRequestReturn(req); err != nil {
+		return nil, err
+	}
+	userID, err := authorizedUser(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.ReturnItem, 0, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		items = append(items, models.ReturnItem{
+			ProductID: item.GetProductId(),
+			VariantID: item.GetVariantId(),
+			Quantity:  item.GetQuantity(),
+		})
+	}
+	ret, err := s.shop.RequestReturn(ctx, userID, req.GetOrderId(), items, req.GetReason())
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, models.ErrOrderNotReturnable):
+			return nil, status.Error(codes.FailedPrecondition, "only paid orders can be returned")
+		case errors.Is(err, models.ErrReturnExceedsOrder):
+			return nil, status.Error(codes.InvalidArgument, "return quantity exceeds ordered quantity")
+		}
+		return nil, status.Error(codes.Internal, "failed to request return")
+	}
+	return toReturnProto(*ret), nil
+}
+
+func (s *ShopServerAPI) ApproveReturn(ctx context.Context, req *shopv1.ApproveReturnRequest) (*shopv1.Return, error) {
+	if req.GetReturnId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "return_id is required")
+	}
+	ret, err := s.shop.ApproveReturn(ctx, req.GetReturnId(), req.GetRestock())
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReturnNotFound):
+			return nil, status.Error(codes.NotFound, "return not found")
+		case errors.Is(err, models.ErrReturnNotApprovable):
+			return nil, status.Error(codes.FailedPrecondition, "return is not awaiting approval")
+		}
+		return nil, status.Error(codes.Internal, "failed to approve return")
+	}
+	return toReturnProto(*ret), nil
+}
+
+func (s *ShopServerAPI) RefundOrder(ctx context.Context, req *shopv1.RefundOrderRequest) (*shopv1.RefundOrderResponse, error) {
+	if err := ValidateRefundOrder(req); err != nil {
+		return nil, err
+	}
+	var amount *models.Money
+	if req.GetAmount() != nil {
+		money, err := moneyFromRequest(req.GetAmount(), 0)
+		if err != nil {
+			return nil, err
+		}
+		amount = &money
+	}
+	return idempotencygrpc.Do(ctx, s.log, s.idempotency, shopv1.ShopService_RefundOrder_FullMethodName, req.GetIdempotencyKey(), req,
+		func() (*shopv1.RefundOrderResponse, error) {
+			result, err := s.shop.RefundOrder(ctx, req.GetOrderId(), amount, req.GetReturnId(), req.GetReason())
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrOrderNotFound):
+					return nil, status.Error(codes.NotFound, "order not found")
+				case errors.Is(err, models.ErrReturnNotFound):
+					return nil, status.Error(codes.NotFound, "return not found")
+				case errors.Is(err, models.ErrPaymentNotFound):
+					return nil, status.Error(codes.FailedPrecondition, "order has no succeeded payment")
+				case errors.Is(err, models.ErrOrderNotReturnable):
+					return nil, status.Error(codes.FailedPrecondition, "order is not paid or already refunded")
+				case errors.Is(err, models.ErrReturnNotApproved):
+					return nil, status.Error(codes.FailedPrecondition, "return is not approved")
+				case errors.Is(err, models.ErrReturnRefunding):
+					return nil, status.Error(codes.FailedPrecondition, "return already has a refund")
+				case errors.Is(err, models.ErrRefundExceedsReturn):
+					return nil, status.Error(codes.FailedPrecondition, "refund exceeds return amount")
+				case errors.Is(err, models.ErrRefundExceedsAmount):
+					return nil, status.Error(codes.FailedPrecondition, "refund exceeds paid amount")
+				case errors.Is(err, models.ErrNothingToRefund):
+					return nil, status.Error(codes.FailedPrecondition, "nothing to refund")
+				case errors.Is(err, models.ErrCurrencyMismatch):
+					return nil, status.Error(codes.InvalidArgument, "amount currency must match order currency")
+				case errors.Is(err, models.ErrPaymentUnavailable):
+					return nil, status.Error(codes.Unavailable, "payment provider unavailable")
+				}
+				return nil, status.Error(codes.Internal, "failed to refund order")
+			}
+			return &shopv1.RefundOrderResponse{
+				Refund:         toRefundProto(result.Refund),
+				OrderStatus:    string(result.Order.Status),
+				RefundedAmount: toMoneyProto(result.Order.RefundedAmount()),
+			}, nil
+		})
+}
+
+func toReturnProto(ret models.Return) *shopv1.Return {
+	result := &shopv1.Return{
+		Id:        ret.ID,
+		OrderId:   ret.OrderID,
+		Status:    string(ret.Status),
+		Reason:    ret.Reason,
+		Restocked: ret.Restocked,
+		Amount:    toMoneyProto(ret.Amount),
+		CreatedAt: ret.CreatedAt.Format(timeLayout),
+	}
+	for _, item := range ret.Items {
+		result.Items = append(result.Items, &shopv1.ReturnItem{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return result
+}
+
+func ValidateRequestReturn(request *shopv1.RequestReturnRequest) error {
+	if request.GetOrderId() <= 0 {
+		return status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	if len(request.GetItems()) == 0 {
+		return status.Error(codes.InvalidArgument, "items are required")
+	}
+	for _, item := range request.GetItems() {
+		if item.GetProductId() <= 0 {
+			return status.Error(codes.InvalidArgument, "items.product_id is required")
+		}
+		if item.GetVariantId() < 0 {
+			return status.Error(codes.InvalidArgument, "items.variant_id cannot be negative")
+		}
+		if item.GetQuantity() <= 0 {
+			return status.Error(codes.InvalidArgument, "items.quantity must be positive")
+		}
+	}
+	return nil
+}
+
+func ValidateRefundOrder(request *shopv1.RefundOrderRequest) error {
+	if request.GetOrderId() <= 0 {
+		return status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	if request.GetReturnId() < 0 {
+		return status.Error(codes.InvalidArgument, "return_id cannot be negative")
+	}
+	if request.GetAmount() != nil && request.GetAmount().GetAmount() <= 0 {
+		return status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+	return nil
+}
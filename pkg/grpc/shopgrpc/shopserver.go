@@ -4,48 +4,156 @@ import (
 	"context"
 	"errors"
 	shopv1 "github.com/kavshevnova/product-reservation-system/gen/go/shop"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/grpc/idempotencygrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"log/slog"
+	"time"
 )
 
 type Shop interface {
-	ListProducts(ctx context.Context, limit int32, offset int32) ([]models.Product, error)
+	ListProducts(ctx context.Context, query models.ProductQuery, pageToken string) (*models.ProductPage, error)
 	GetProductInfo(ctx context.Context, productID int64) (*models.Product, error)
-	MakeOrder(ctx context.Context, userID, productID int64, quantity int32) (*models.Order, error)
-	GetOrdersHistory(ctx context.Context, userID int64) ([]models.Order, error)
-	ConfirmPayment(ctx context.Context, orderID int64, success bool) error
+	SearchProducts(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error)
+	ListCategories(ctx context.Context, parentID int64, all bool) ([]models.Category, error)
+	GetCategoryTree(ctx context.Context, rootID int64) ([]*models.CategoryNode, error)
+	MakeOrder(ctx context.Context, userID int64, items []models.OrderItem) (*models.Order, error)
+	GetOrdersHistory(ctx context.Context, filter models.OrderHistoryFilter, pageToken string) (*models.OrderPage, error)
+	ConfirmPayment(ctx context.Context, result models.PaymentResult) error
+	CreateProduct(ctx context.Context, product models.Product) (*models.Product, error)
+	UpdateProduct(ctx context.Context, productID int64, update models.ProductUpdate) (*models.Product, error)
+	DeleteProduct(ctx context.Context, productID int64) error
+	UpdateOrderStatus(ctx context.Context, orderID int64, status models.OrderStatus) (*models.Order, error)
+	CancelOrder(ctx context.Context, userID, orderID int64, reason string) (*models.Order, error)
+	GetOrder(ctx context.Context, viewer models.Principal, orderID int64) (*models.Order, error)
+	RequestReturn(ctx context.Context, userID, orderID int64, items []models.ReturnItem, reason string) (*models.Return, error)
+	ApproveReturn(ctx context.Context, returnID int64, restock bool) (*models.Return, error)
+	RefundOrder(ctx context.Context, orderID int64, amount *models.Money, returnID int64, reason string) (*models.RefundResult, error)
 }
 
 type ShopServerAPI struct {
 	shopv1.UnimplementedShopServiceServer
-	shop Shop
+	log         *slog.Logger
+	shop        Shop
+	idempotency idempotencygrpc.Store
 }
 
-func RegisterShopServerAPI(grpcServer *grpc.Server, shop Shop) {
-	shopv1.RegisterShopServiceServer(grpcServer, &ShopServerAPI{shop: shop})
+func RegisterShopServerAPI(grpcServer *grpc.Server, log *slog.Logger, shop Shop, idempotency idempotencygrpc.Store) {
+	shopv1.RegisterShopServiceServer(grpcServer, &ShopServerAPI{log: log, shop: shop, idempotency: idempotency})
 }
 
 func (s *ShopServerAPI) ListProducts(ctx context.Context, req *shopv1.ListProductsRequest) (*shopv1.ListProductsResponse, error) {
 	if err := ValidateListProducts(req); err != nil {
 		return nil, err
 	}
-	products, err := s.shop.ListProducts(ctx, req.GetLimit(), req.GetOffset())
+	minPrice, maxPrice, err := priceFilterFromRequest(req.GetFilter())
 	if err != nil {
+		return nil, err
+	}
+	query := models.ProductQuery{
+		Filter: models.ProductFilter{
+			MinPrice:     minPrice,
+			MaxPrice:     maxPrice,
+			InStockOnly:  req.GetFilter().GetInStockOnly(),
+			NameContains: req.GetFilter().GetNameContains(),
+			CategoryID:   req.GetFilter().GetCategoryId(),
+		},
+		Sort:      models.ProductSort(req.GetSort()),
+		Limit:     req.GetLimit(),
+		Offset:    req.GetOffset(),
+		WithTotal: req.GetIncludeTotalCount(),
+	}
+	page, err := s.shop.ListProducts(ctx, query, req.GetPageToken())
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
 		return nil, status.Error(codes.Internal, "failed to list products")
 	}
 	var listProducts []*shopv1.Product
-	for _, product := range products {
+	for _, product := range page.Products {
 		listProducts = append(listProducts, &shopv1.Product{
-			ProductId: product.ProductID,
-			Name:      product.Name,
-			Price:     product.Price,
-			Stock:     product.Stock,
+			ProductId:  product.ProductID,
+			Name:       product.Name,
+			Price:      product.Price.Float32(),
+			PriceMoney: toMoneyProto(product.Price),
+			Stock:      product.Stock,
+		})
+	}
+	return &shopv1.ListProductsResponse{
+		Products:      listProducts,
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
+	}, nil
+}
+
+func (s *ShopServerAPI) SearchProducts(ctx context.Context, req *shopv1.SearchProductsRequest) (*shopv1.SearchProductsResponse, error) {
+	if err := ValidateSearchProducts(req); err != nil {
+		return nil, err
+	}
+	hits, err := s.shop.SearchProducts(ctx, req.GetQuery(), req.GetLimit())
+	if err != nil {
+		if errors.Is(err, models.ErrEmptySearch) {
+			return nil, status.Error(codes.InvalidArgument, "query is required")
+		}
+		return nil, status.Error(codes.Internal, "failed to search products")
+	}
+	resp := &shopv1.SearchProductsResponse{}
+	for _, hit := range hits {
+		resp.Hits = append(resp.Hits, &shopv1.ProductSearchHit{
+			Product: &shopv1.Product{
+				ProductId:  hit.ProductID,
+				Name:       hit.Name,
+				Price:      hit.Price.Float32(),
+				PriceMoney: toMoneyProto(hit.Price),
+				Stock:      hit.Stock,
+			},
+			Rank:    hit.Rank,
+			Snippet: hit.Snippet,
 		})
+		resp.Fuzzy = hit.Fuzzy
+	}
+	return resp, nil
+}
+
+func (s *ShopServerAPI) ListCategories(ctx context.Context, req *shopv1.ListCategoriesRequest) (*shopv1.ListCategoriesResponse, error) {
+	if req.GetParentId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "parent_id cannot be negative")
+	}
+	categories, err := s.shop.ListCategories(ctx, req.GetParentId(), req.GetAll())
+	if err != nil {
+		if errors.Is(err, models.ErrCategoryNotFound) {
+			return nil, status.Error(codes.NotFound, "category not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to list categories")
+	}
+	resp := &shopv1.ListCategoriesResponse{}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, toCategoryProto(category))
+	}
+	return resp, nil
+}
+
+func (s *ShopServerAPI) GetCategoryTree(ctx context.Context, req *shopv1.GetCategoryTreeRequest) (*shopv1.GetCategoryTreeResponse, error) {
+	if req.GetRootId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "root_id cannot be negative")
+	}
+	roots, err := s.shop.GetCategoryTree(ctx, req.GetRootId())
+	if err != nil {
+		if errors.Is(err, models.ErrCategoryNotFound) {
+			return nil, status.Error(codes.NotFound, "category not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get category tree")
+	}
+	resp := &shopv1.GetCategoryTreeResponse{}
+	for _, root := range roots {
+		resp.Roots = append(resp.Roots, toCategoryNodeProto(root))
 	}
-	return &shopv1.ListProductsResponse{Products: listProducts}, nil
+	return resp, nil
 }
 
 func (s *ShopServerAPI) GetProductInfo(ctx context.Context, req *shopv1.GetProductInfoRequest) (*shopv1.GetProductInfoResponse, error) {
@@ -60,12 +168,24 @@ func (s *ShopServerAPI) GetProductInfo(ctx context.Context, req *shopv1.GetProdu
 		}
 		return nil, status.Error(codes.Internal, "failed to get product")
 	}
-	return &shopv1.GetProductInfoResponse{
-		ProductId: product.ProductID,
-		Name:      product.Name,
-		Price:     product.Price,
-		Stock:     product.Stock,
-	}, nil
+	resp := &shopv1.GetProductInfoResponse{
+		ProductId:  product.ProductID,
+		Name:       product.Name,
+		Price:      product.Price.Float32(),
+		PriceMoney: toMoneyProto(product.Price),
+		Stock:      product.Stock,
+	}
+	for _, variant := range product.Variants {
+		resp.Variants = append(resp.Variants, &shopv1.ProductVariant{
+			VariantId:  variant.ID,
+			Sku:        variant.SKU,
+			Attributes: variant.Attributes,
+			Price:      variant.Price.Float32(),
+			PriceMoney: toMoneyProto(variant.Price),
+			Stock:      variant.Stock,
+		})
+	}
+	return resp, nil
 
 }
 
@@ -73,54 +193,352 @@ func (s *ShopServerAPI) MakeOrder(ctx context.Context, req *shopv1.MakeOrderRequ
 	if err := ValidateOrderRequest(req); err != nil {
 		return nil, err
 	}
-	order, err := s.shop.MakeOrder(ctx, req.GetUserId(), req.GetProductId(), req.GetQuantity())
+	userID, err := authorizedUser(ctx, req.GetUserId())
 	if err != nil {
-		switch {
-		case errors.Is(err, models.ErrProductNotFound):
-			return nil, status.Error(codes.NotFound, "product not found")
-		case errors.Is(err, models.ErrNotEnoughStock):
-			return &shopv1.MakeOrderResponse{OrderId: order.ID, Status: "Not enough stock"}, nil
-		default:
-			return nil, status.Error(codes.Internal, "failed to make order")
-		}
+		return nil, err
 	}
-	return &shopv1.MakeOrderResponse{
-		OrderId:    order.ID,
-		PaymentURL: order.PaymentURL,
-		Status:     order.Status,
-	}, nil
+	return idempotencygrpc.Do(ctx, s.log, s.idempotency, shopv1.ShopService_MakeOrder_FullMethodName, req.GetIdempotencyKey(), req,
+		func() (*shopv1.MakeOrderResponse, error) {
+			order, err := s.shop.MakeOrder(ctx, userID, orderItemsFromRequest(req))
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrProductNotFound):
+					return nil, status.Error(codes.NotFound, "product not found")
+				case errors.Is(err, models.ErrVariantNotFound):
+					return nil, status.Error(codes.NotFound, "variant not found")
+				case errors.Is(err, models.ErrVariantRequired):
+					return nil, status.Error(codes.InvalidArgument, "variant_id is required for products sold by variants")
+				case errors.Is(err, models.ErrNotEnoughStock):
+					return &shopv1.MakeOrderResponse{Status: "Not enough stock"}, nil
+				case errors.Is(err, models.ErrPaymentUnavailable):
+					return nil, status.Error(codes.Unavailable, "payment provider unavailable")
+				default:
+					return nil, status.Error(codes.Internal, "failed to make order")
+				}
+			}
+			return &shopv1.MakeOrderResponse{
+				OrderId:    order.ID,
+				PaymentURL: order.PaymentURL,
+				Status:     string(order.Status),
+				Items:      toOrderItemsProto(order.Items),
+				Sum:        order.Sum.Float32(),
+				SumMoney:   toMoneyProto(order.Sum),
+			}, nil
+		})
 }
 
 func (s *ShopServerAPI) GetOrdersHistory(ctx context.Context, req *shopv1.OrdersHistoryRequest) (*shopv1.OrdersHistoryResponse, error) {
-	if req.GetUserId() == 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	userID, err := authorizedUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	filter, err := ValidateOrdersHistory(req)
+	if err != nil {
+		return nil, err
 	}
-	orderHistory, err := s.shop.GetOrdersHistory(ctx, req.GetUserId())
+	filter.UserID = userID
+
+	page, err := s.shop.GetOrdersHistory(ctx, filter, req.GetPageToken())
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
 		return nil, status.Error(codes.Internal, "failed to get order history")
 	}
 	var listOrders []*shopv1.Order
-	for _, orders := range orderHistory {
-		listOrders = append(listOrders, &shopv1.Order{
-			Id:        orders.ID,
-			UserId:    orders.UserID,
-			ProductId: orders.ProductID,
-			Quantity:  orders.Quantity,
-			Sum:       orders.Sum,
-			OrderTime: orders.Time.Format("2006-01-02 15:04:05.999999999"),
-			Status:    orders.Status,
-		})
+	for _, orders := range page.Orders {
+		listOrders = append(listOrders, toOrderProto(orders))
 	}
-	return &shopv1.OrdersHistoryResponse{Orders: listOrders}, nil
+	return &shopv1.OrdersHistoryResponse{Orders: listOrders, NextPageToken: page.NextPageToken}, nil
 }
 
 func (s *ShopServerAPI) ConfirmPayment(ctx context.Context, req *shopv1.PaymentConfirmation) (*emptypb.Empty, error) {
-	if err := s.shop.ConfirmPayment(ctx, req.GetOrderId(), req.GetSuccess()); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	return idempotencygrpc.Do(ctx, s.log, s.idempotency, shopv1.ShopService_ConfirmPayment_FullMethodName, req.GetIdempotencyKey(), req,
+		func() (*emptypb.Empty, error) {
+			result := models.PaymentResult{OrderID: req.GetOrderId(), Success: req.GetSuccess()}
+			if err := s.shop.ConfirmPayment(ctx, result); err != nil {
+				switch {
+				case errors.Is(err, models.ErrOrderNotFound):
+					return nil, status.Error(codes.NotFound, "order not found")
+				case errors.Is(err, models.ErrInvalidTransition):
+					return nil, status.Error(codes.FailedPrecondition, "order is not awaiting payment")
+				}
+				return nil, status.Error(codes.Internal, "failed to confirm payment")
+			}
+			return &emptypb.Empty{}, nil
+		})
+}
+
+func (s *ShopServerAPI) GetOrder(ctx context.Context, req *shopv1.GetOrderRequest) (*shopv1.Order, error) {
+	if req.GetOrderId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	principal, ok := authctx.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	order, err := s.shop.GetOrder(ctx, principal, req.GetOrderId())
+	if err != nil {
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get order")
+	}
+
+	resp := toOrderProto(*order)
+	if !order.ExpiresAt.IsZero() && order.Status == models.OrderStatusReserved {
+		resp.ExpiresAt = order.ExpiresAt.Format(timeLayout)
+	}
+	for _, change := range order.StatusHistory {
+		resp.StatusHistory = append(resp.StatusHistory, &shopv1.OrderStatusChange{
+			From:  string(change.From),
+			To:    string(change.To),
+			Actor: change.Actor,
+			Time:  change.Time.Format(timeLayout),
+		})
+	}
+	resp.Payment = toPaymentInfoProto(*order)
+	for _, ret := range order.Returns {
+		resp.Returns = append(resp.Returns, toReturnProto(ret))
+	}
+	return resp, nil
+}
+
+func (s *ShopServerAPI) CancelOrder(ctx context.Context, req *shopv1.CancelOrderRequest) (*shopv1.CancelOrderResponse, error) {
+	if req.GetOrderId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	userID, err := authorizedUser(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.shop.CancelOrder(ctx, userID, req.GetOrderId(), req.GetReason())
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, models.ErrOrderNotCancelable):
+			return nil, status.Error(codes.FailedPrecondition, "order cannot be canceled")
+		}
+		return nil, status.Error(codes.Internal, "failed to cancel order")
+	}
+	resp := &shopv1.CancelOrderResponse{
+		OrderId: order.ID,
+		Status:  string(order.Status),
+	}
+	var refunded models.Money
+	for _, refund := range order.Refunds {
+		resp.RefundRequested = true
+		refunded, err = refunded.Add(refund.Amount)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to cancel order")
+		}
+	}
+	if resp.RefundRequested {
+		resp.RefundAmount = refunded.Float32()
+		resp.RefundAmountMoney = toMoneyProto(refunded)
+	}
+	return resp, nil
+}
+
+func (s *ShopServerAPI) UpdateOrderStatus(ctx context.Context, req *shopv1.UpdateOrderStatusRequest) (*shopv1.Order, error) {
+	if req.GetOrderId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	order, err := s.shop.UpdateOrderStatus(ctx, req.GetOrderId(), models.OrderStatus(req.GetStatus()))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidStatus):
+			return nil, status.Error(codes.InvalidArgument, "status must be shipped or delivered")
+		case errors.Is(err, models.ErrOrderNotFound):
+			return nil, status.Error(codes.NotFound, "order not found")
+		case errors.Is(err, models.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, "order cannot move to this status")
+		}
+		return nil, status.Error(codes.Internal, "failed to update order status")
+	}
+	return toOrderProto(*order), nil
+}
+
+func (s *ShopServerAPI) CreateProduct(ctx context.Context, req *shopv1.CreateProductRequest) (*shopv1.Product, error) {
+	if err := ValidateCreateProduct(req); err != nil {
+		return nil, err
+	}
+	price, err := moneyFromRequest(req.GetPriceMoney(), req.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.shop.CreateProduct(ctx, models.Product{
+		Name:  req.GetName(),
+		Price: price,
+		Stock: req.GetStock(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create product")
+	}
+	return toProductProto(product), nil
+}
+
+func (s *ShopServerAPI) UpdateProduct(ctx context.Context, req *shopv1.UpdateProductRequest) (*shopv1.Product, error) {
+	update, err := ValidateUpdateProduct(req)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.shop.UpdateProduct(ctx, req.GetProduct().GetProductId(), update)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			return nil, status.Error(codes.NotFound, "product not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to update product")
+	}
+	return toProductProto(product), nil
+}
+
+func (s *ShopServerAPI) DeleteProduct(ctx context.Context, req *shopv1.DeleteProductRequest) (*emptypb.Empty, error) {
+	if req.GetProductId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if err := s.shop.DeleteProduct(ctx, req.GetProductId()); err != nil {
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			return nil, status.Error(codes.NotFound, "product not found")
+		case errors.Is(err, models.ErrProductInUse):
+			return nil, status.Error(codes.FailedPrecondition, "product has orders and cannot be deleted")
+		}
+		return nil, status.Error(codes.Internal, "failed to delete product")
 	}
 	return &emptypb.Empty{}, nil
 }
 
+func orderItemsFromRequest(req *shopv1.MakeOrderRequest) []models.OrderItem {
+	if len(req.GetItems()) == 0 {
+		return []models.OrderItem{{ProductID: req.GetProductId(), Quantity: req.GetQuantity()}}
+	}
+	items := make([]models.OrderItem, 0, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		items = append(items, models.OrderItem{ProductID: item.GetProductId(), VariantID: item.GetVariantId(), Quantity: item.GetQuantity()})
+	}
+	return items
+}
+
+const timeLayout = "2006-01-02 15:04:05.999999999"
+
+func toOrderProto(order models.Order) *shopv1.Order {
+	return &shopv1.Order{
+		Id:             order.ID,
+		UserId:         order.UserID,
+		ProductId:      order.ProductID,
+		Quantity:       order.Quantity,
+		Sum:            order.Sum.Float32(),
+		SumMoney:       toMoneyProto(order.Sum),
+		OrderTime:      order.Time.Format(timeLayout),
+		Status:         string(order.Status),
+		Items:          toOrderItemsProto(order.Items),
+		RefundedAmount: toMoneyProto(order.RefundedAmount()),
+	}
+}
+
+func toPaymentInfoProto(order models.Order) *shopv1.PaymentInfo {
+	refunded := order.RefundedAmount()
+	info := &shopv1.PaymentInfo{
+		PaymentUrl:          order.PaymentURL,
+		RefundedAmount:      refunded.Float32(),
+		RefundedAmountMoney: toMoneyProto(refunded),
+	}
+	paidAt := order.PaidAt()
+	switch {
+	case order.Status == models.OrderStatusReserved:
+		info.Status = "pending"
+	case paidAt.IsZero():
+		info.Status = "not_paid"
+	case refunded.Amount >= order.Sum.Amount:
+		info.Status = "refunded"
+	case order.PendingRefundAmount().Amount > 0:
+		//Возврат создан, но провайдер еще не вернул деньги
+		info.Status = "refund_pending"
+	case refunded.Amount > 0:
+		info.Status = "partially_refunded"
+	default:
+		info.Status = "paid"
+	}
+	if !paidAt.IsZero() {
+		info.PaidAt = paidAt.Format(timeLayout)
+	}
+	for _, refund := range order.Refunds {
+		info.Refunds = append(info.Refunds, toRefundProto(refund))
+	}
+	for _, payment := range order.Payments {
+		info.Payments = append(info.Payments, &shopv1.Payment{
+			Id:         payment.ID,
+			Provider:   payment.Provider,
+			ExternalId: payment.ExternalID,
+			Amount:     toMoneyProto(payment.Amount),
+			Status:     string(payment.Status),
+			Attempts:   payment.Attempts,
+			CreatedAt:  payment.CreatedAt.Format(timeLayout),
+			UpdatedAt:  payment.UpdatedAt.Format(timeLayout),
+		})
+	}
+	return info
+}
+
+func toRefundProto(refund models.Refund) *shopv1.Refund {
+	return &shopv1.Refund{
+		Id:          refund.ID,
+		Amount:      refund.Amount.Float32(),
+		AmountMoney: toMoneyProto(refund.Amount),
+		Status:      string(refund.Status),
+		Reason:      refund.Reason,
+		CreatedAt:   refund.CreatedAt.Format(timeLayout),
+		ReturnId:    refund.ReturnID,
+	}
+}
+
+func toCategoryProto(category models.Category) *shopv1.Category {
+	return &shopv1.Category{
+		CategoryId: category.ID,
+		ParentId:   category.ParentID,
+		Name:       category.Name,
+		Slug:       category.Slug,
+	}
+}
+
+func toCategoryNodeProto(node *models.CategoryNode) *shopv1.CategoryNode {
+	result := &shopv1.CategoryNode{Category: toCategoryProto(node.Category)}
+	for _, child := range node.Children {
+		result.Children = append(result.Children, toCategoryNodeProto(child))
+	}
+	return result
+}
+
+func toOrderItemsProto(items []models.OrderItem) []*shopv1.OrderItem {
+	result := make([]*shopv1.OrderItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, &shopv1.OrderItem{
+			ProductId:   item.ProductID,
+			VariantId:   item.VariantID,
+			Sku:         item.SKU,
+			Quantity:    item.Quantity,
+			ProductName: item.ProductName,
+			Price:       item.Price.Float32(),
+			Sum:         item.Sum.Float32(),
+			PriceMoney:  toMoneyProto(item.Price),
+			SumMoney:    toMoneyProto(item.Sum),
+		})
+	}
+	return result
+}
+
+func toProductProto(product *models.Product) *shopv1.Product {
+	return &shopv1.Product{
+		ProductId:  product.ProductID,
+		Name:       product.Name,
+		Price:      product.Price.Float32(),
+		PriceMoney: toMoneyProto(product.Price),
+		Stock:      product.Stock,
+	}
+}
+
 func (s *ShopServerAPI) mustEmbedUnimplementedShopServiceServer() {}
 
 func ValidateListProducts(request *shopv1.ListProductsRequest) error {
@@ -130,18 +548,175 @@ func ValidateListProducts(request *shopv1.ListProductsRequest) error {
 	if request.GetOffset() < 0 {
 		return status.Error(codes.InvalidArgument, "offset cannot be negative")
 	}
+	if request.GetOffset() > 0 && request.GetPageToken() != "" {
+		return status.Error(codes.InvalidArgument, "use either offset or page_token")
+	}
+	if !models.ProductSort(request.GetSort()).Valid() {
+		return status.Errorf(codes.InvalidArgument, "unknown sort %q", request.GetSort())
+	}
+	filter := request.GetFilter()
+	minPrice, maxPrice, err := priceFilterFromRequest(filter)
+	if err != nil {
+		return err
+	}
+	if (minPrice != nil && minPrice.Amount < 0) || (maxPrice != nil && maxPrice.Amount < 0) {
+		return status.Error(codes.InvalidArgument, "price filter cannot be negative")
+	}
+	if minPrice != nil && maxPrice != nil {
+		if minPrice.Currency != maxPrice.Currency {
+			return status.Error(codes.InvalidArgument, "min_price and max_price must be in the same currency")
+		}
+		if minPrice.Amount > maxPrice.Amount {
+			return status.Error(codes.InvalidArgument, "min_price cannot be greater than max_price")
+		}
+	}
+	if len(filter.GetNameContains()) > 255 {
+		return status.Error(codes.InvalidArgument, "name_contains is too long")
+	}
+	return nil
+}
+
+func ValidateSearchProducts(request *shopv1.SearchProductsRequest) error {
+	if request.GetQuery() == "" {
+		return status.Error(codes.InvalidArgument, "query is required")
+	}
+	if len(request.GetQuery()) > 255 {
+		return status.Error(codes.InvalidArgument, "query is too long")
+	}
+	if request.GetLimit() < 0 {
+		return status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
 	return nil
 }
 
+func ValidateOrdersHistory(request *shopv1.OrdersHistoryRequest) (models.OrderHistoryFilter, error) {
+	filter := models.OrderHistoryFilter{
+		ProductID: request.GetProductId(),
+		Limit:     request.GetPageSize(),
+	}
+	if request.GetPageSize() < 0 {
+		return filter, status.Error(codes.InvalidArgument, "page_size cannot be negative")
+	}
+	for _, name := range request.GetStatuses() {
+		orderStatus := models.OrderStatus(name)
+		if !orderStatus.Valid() {
+			return filter, status.Errorf(codes.InvalidArgument, "unknown status %q", name)
+		}
+		filter.Statuses = append(filter.Statuses, orderStatus)
+	}
+	var err error
+	if request.GetFromTime() != "" {
+		if filter.From, err = time.Parse(time.RFC3339, request.GetFromTime()); err != nil {
+			return filter, status.Error(codes.InvalidArgument, "from_time must be RFC 3339")
+		}
+	}
+	if request.GetToTime() != "" {
+		if filter.To, err = time.Parse(time.RFC3339, request.GetToTime()); err != nil {
+			return filter, status.Error(codes.InvalidArgument, "to_time must be RFC 3339")
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, status.Error(codes.InvalidArgument, "from_time must be before to_time")
+	}
+	return filter, nil
+}
+
 func ValidateOrderRequest(request *shopv1.MakeOrderRequest) error {
-	if request.GetProductId() <= 0 {
-		return status.Error(codes.InvalidArgument, "product_id is required")
+	if len(request.GetItems()) > 0 {
+		if request.GetProductId() != 0 || request.GetQuantity() != 0 {
+			return status.Error(codes.InvalidArgument, "use either items or product_id/quantity")
+		}
+		for _, item := range request.GetItems() {
+			if item.GetProductId() <= 0 {
+				return status.Error(codes.InvalidArgument, "items.product_id is required")
+			}
+			if item.GetQuantity() <= 0 {
+				return status.Error(codes.InvalidArgument, "items.quantity must be positive")
+			}
+		}
+	} else {
+		if request.GetProductId() <= 0 {
+			return status.Error(codes.InvalidArgument, "product_id is required")
+		}
+		if request.GetQuantity() <= 0 {
+			return status.Error(codes.InvalidArgument, "quantity must be positive")
+		}
+	}
+	if request.GetUserId() < 0 {
+		return status.Error(codes.InvalidArgument, "user_id cannot be negative")
+	}
+	return nil
+}
+
+// authorizedUser возвращает id пользователя из токена.
+// user_id из запроса необязателен, но если передан, то должен совпадать с токеном.
+func authorizedUser(ctx context.Context, requestedUserID int64) (int64, error) {
+	principal, ok := authctx.PrincipalFromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if requestedUserID != 0 && requestedUserID != principal.UserID {
+		return 0, status.Error(codes.PermissionDenied, "user_id does not match authenticated user")
+	}
+	return principal.UserID, nil
+}
+
+func ValidateCreateProduct(request *shopv1.CreateProductRequest) error {
+	if request.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	price, err := moneyFromRequest(request.GetPriceMoney(), request.GetPrice())
+	if err != nil {
+		return err
 	}
-	if request.GetQuantity() <= 0 {
-		return status.Error(codes.InvalidArgument, "quantity must be positive")
+	if price.Amount <= 0 {
+		return status.Error(codes.InvalidArgument, "price must be positive")
 	}
-	if request.GetUserId() <= 0 {
-		return status.Error(codes.InvalidArgument, "user_id is required")
+	if request.GetStock() < 0 {
+		return status.Error(codes.InvalidArgument, "stock cannot be negative")
 	}
 	return nil
 }
+
+// ValidateUpdateProduct проверяет запрос и переводит маску полей в частичное обновление
+func ValidateUpdateProduct(request *shopv1.UpdateProductRequest) (models.ProductUpdate, error) {
+	var update models.ProductUpdate
+
+	product := request.GetProduct()
+	if product.GetProductId() <= 0 {
+		return update, status.Error(codes.InvalidArgument, "product.product_id is required")
+	}
+
+	paths := request.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"name", "price", "stock"}
+	}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			if product.GetName() == "" {
+				return update, status.Error(codes.InvalidArgument, "name cannot be empty")
+			}
+			name := product.GetName()
+			update.Name = &name
+		case "price":
+			price, err := moneyFromRequest(product.GetPriceMoney(), product.GetPrice())
+			if err != nil {
+				return update, err
+			}
+			if price.Amount <= 0 {
+				return update, status.Error(codes.InvalidArgument, "price must be positive")
+			}
+			update.Price = &price
+		case "stock":
+			if product.GetStock() < 0 {
+				return update, status.Error(codes.InvalidArgument, "stock cannot be negative")
+			}
+			stock := product.GetStock()
+			update.Stock = &stock
+		default:
+			return update, status.Errorf(codes.InvalidArgument, "unknown field in update_mask: %s", path)
+		}
+	}
+	return update, nil
+}
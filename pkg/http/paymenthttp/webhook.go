@@ -0,0 +1,176 @@
+package paymenthttp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+const (
+	SignatureHeader = "X-Payment-Signature"
+	TimestampHeader = "X-Payment-Timestamp"
+
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// maxBodySize - вебхук провайдера - короткий JSON, большие тела не читаем
+const maxBodySize = 64 << 10
+
+type PaymentConfirmer interface {
+	ConfirmPayment(ctx context.Context, result models.PaymentResult) error
+}
+
+// EventStore помнит обработанные события, чтобы перехваченный вебхук нельзя было прислать повторно
+type EventStore interface {
+	SaveWebhookEvent(ctx context.Context, provider, eventID string) (bool, error)
+	ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error
+}
+
+// WebhookEvent - тело вебхука провайдера
+type WebhookEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	OrderID   int64  `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+}
+
+type WebhookHandler struct {
+	log       *slog.Logger
+	shop      PaymentConfirmer
+	events    EventStore
+	provider  string
+	secret    []byte
+	tolerance time.Duration
+}
+
+func NewWebhookHandler(log *slog.Logger, shop PaymentConfirmer, events EventStore, provider, secret string, tolerance time.Duration) *WebhookHandler {
+	return &WebhookHandler{
+		log:       log,
+		shop:      shop,
+		events:    events,
+		provider:  provider,
+		secret:    []byte(secret),
+		tolerance: tolerance,
+	}
+}
+
+// Sign - подпись вебхука: hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+// Метка времени входит в подпись, поэтому старое тело нельзя переотправить со свежей меткой
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "paymenthttp.Webhook"
+
+	log := h.log.With(slog.String("operation", op), slog.String("provider", h.provider))
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodySize {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	//Сначала подпись и метка времени, и только потом разбираем тело
+	if err := h.verify(r.Header, body, time.Now()); err != nil {
+		log.Warn("Rejected webhook", slog.String("error", err.Error()))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" || event.OrderID <= 0 || event.PaymentID == "" {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+	if event.Type != EventPaymentSucceeded && event.Type != EventPaymentFailed {
+		//Другие события нам не нужны, но провайдер не должен их повторять
+		log.Info("Ignored webhook event", slog.String("type", event.Type))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	log = log.With(slog.String("event_id", event.ID), slog.Int64("order_id", event.OrderID))
+
+	fresh, err := h.events.SaveWebhookEvent(r.Context(), h.provider, event.ID)
+	if err != nil {
+		log.Error("Failed to save webhook event", slog.String("error", err.Error()))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !fresh {
+		log.Warn("Webhook replay rejected")
+		http.Error(w, "event already processed", http.StatusConflict)
+		return
+	}
+
+	err = h.shop.ConfirmPayment(r.Context(), models.PaymentResult{
+		OrderID:    event.OrderID,
+		ExternalID: event.PaymentID,
+		Success:    event.Type == EventPaymentSucceeded,
+		RawPayload: body,
+	})
+	switch {
+	case err == nil:
+		log.Info("Webhook processed", slog.String("type", event.Type))
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, models.ErrOrderNotFound), errors.Is(err, models.ErrPaymentNotFound):
+		http.Error(w, "payment not found", http.StatusNotFound)
+	case errors.Is(err, models.ErrInvalidTransition):
+		//Заказ уже оплачен, отменен или истек - повтор события ничего не изменит
+		log.Warn("Webhook for order in final state", slog.String("error", err.Error()))
+		http.Error(w, "order cannot change payment state", http.StatusConflict)
+	default:
+		//Временная ошибка: забываем событие, чтобы провайдер мог его повторить
+		log.Error("Failed to process webhook", slog.String("error", err.Error()))
+		if releaseErr := h.events.ReleaseWebhookEvent(r.Context(), h.provider, event.ID); releaseErr != nil {
+			log.Error("Failed to release webhook event", slog.String("error", releaseErr.Error()))
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+var (
+	errMissingSignature = errors.New("missing signature or timestamp")
+	errStaleTimestamp   = errors.New("timestamp outside tolerance")
+	errBadSignature     = errors.New("signature mismatch")
+)
+
+func (h *WebhookHandler) verify(header http.Header, body []byte, now time.Time) error {
+	signature := header.Get(SignatureHeader)
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if signature == "" || err != nil {
+		return errMissingSignature
+	}
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > h.tolerance || age < -h.tolerance {
+		return errStaleTimestamp
+	}
+	expected := Sign(h.secret, timestamp, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package paymenthttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+const (
+	testSecret    = "webhook-secret"
+	testTolerance = 5 * time.Minute
+)
+
+type fakeShop struct {
+	mu      sync.Mutex
+	err     error
+	results []models.PaymentResult
+}
+
+func (s *fakeShop) ConfirmPayment(_ context.Context, result models.PaymentResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	return s.err
+}
+
+type fakeEvents struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (e *fakeEvents) SaveWebhookEvent(_ context.Context, provider, eventID string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := provider + "/" + eventID
+	if e.seen[key] {
+		return false, nil
+	}
+	e.seen[key] = true
+	return true, nil
+}
+
+func (e *fakeEvents) ReleaseWebhookEvent(_ context.Context, provider, eventID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.seen, provider+"/"+eventID)
+	return nil
+}
+
+func newTestHandler() (*WebhookHandler, *fakeShop) {
+	shop := &fakeShop{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewWebhookHandler(log, shop, &fakeEvents{seen: make(map[string]bool)}, "fake", testSecret, testTolerance), shop
+}
+
+const testBody = `{"id":"evt_1","type":"payment.succeeded","order_id":42,"payment_id":"pay_1"}`
+
+func webhookRequest(body string, timestamp int64, signature string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/payment", bytes.NewBufferString(body))
+	if timestamp != 0 {
+		r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	}
+	if signature != "" {
+		r.Header.Set(SignatureHeader, signature)
+	}
+	return r
+}
+
+func signedRequest(body string, timestamp int64) *http.Request {
+	return webhookRequest(body, timestamp, Sign([]byte(testSecret), timestamp, []byte(body)))
+}
+
+func serve(h http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestSign(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("1700000000." + testBody))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign([]byte(testSecret), 1700000000, []byte(testBody)); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+	//Метка времени входит в подпись
+	if Sign([]byte(testSecret), 1700000001, []byte(testBody)) == want {
+		t.Fatal("signature does not depend on timestamp")
+	}
+	if Sign([]byte("other"), 1700000000, []byte(testBody)) == want {
+		t.Fatal("signature does not depend on secret")
+	}
+}
+
+func TestWebhookAcceptsSignedEvent(t *testing.T) {
+	handler, shop := newTestHandler()
+
+	if code := serve(handler, signedRequest(testBody, time.Now().Unix())); code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", code)
+	}
+	if len(shop.results) != 1 {
+		t.Fatalf("ConfirmPayment called %d times, want 1", len(shop.results))
+	}
+	got := shop.results[0]
+	if got.OrderID != 42 || got.ExternalID != "pay_1" || !got.Success || string(got.RawPayload) != testBody {
+		t.Fatalf("payment result = %+v", got)
+	}
+}
+
+func TestWebhookRejectsUnverified(t *testing.T) {
+	now := time.Now().Unix()
+	tolerance := int64(testTolerance / time.Second)
+
+	tests := []struct {
+		name    string
+		request *http.Request
+	}{
+		{name: "no headers", request: webhookRequest(testBody, 0, "")},
+		{name: "no signature", request: webhookRequest(testBody, now, "")},
+		{name: "no timestamp", request: webhookRequest(testBody, 0, Sign([]byte(testSecret), now, []byte(testBody)))},
+		{name: "bad timestamp", request: func() *http.Request {
+			r := signedRequest(testBody, now)
+			r.Header.Set(TimestampHeader, "yesterday")
+			return r
+		}()},
+		{name: "wrong secret", request: webhookRequest(testBody, now, Sign([]byte("other"), now, []byte(testBody)))},
+		{name: "garbage signature", request: webhookRequest(testBody, now, "deadbeef")},
+		{
+			name:    "body changed after signing",
+			request: webhookRequest(`{"id":"evt_1","type":"payment.succeeded","order_id":43,"payment_id":"pay_1"}`, now, Sign([]byte(testSecret), now, []byte(testBody))),
+		},
+		{
+			name:    "signature for another timestamp",
+			request: webhookRequest(testBody, now, Sign([]byte(testSecret), now-60, []byte(testBody))),
+		},
+		{name: "too old", request: signedRequest(testBody, now-tolerance-60)},
+		{name: "too far in future", request: signedRequest(testBody, now+tolerance+60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, shop := newTestHandler()
+			if code := serve(handler, tt.request); code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", code)
+			}
+			if len(shop.results) != 0 {
+				t.Fatalf("ConfirmPayment called for rejected webhook")
+			}
+		})
+	}
+}
+
+func TestWebhookAcceptsTimestampWithinTolerance(t *testing.T) {
+	now := time.Now().Unix()
+	tolerance := int64(testTolerance / time.Second)
+
+	for _, timestamp := range []int64{now - tolerance + 30, now + tolerance - 30} {
+		handler, _ := newTestHandler()
+		if code := serve(handler, signedRequest(testBody, timestamp)); code != http.StatusNoContent {
+			t.Fatalf("timestamp %+d s: status = %d, want 204", timestamp-now, code)
+		}
+	}
+}
+
+func TestWebhookRejectsReplay(t *testing.T) {
+	handler, shop := newTestHandler()
+	now := time.Now().Unix()
+
+	if code := serve(handler, signedRequest(testBody, now)); code != http.StatusNoContent {
+		t.Fatalf("first delivery status = %d, want 204", code)
+	}
+	//Тот же запрос целиком и то же событие, переподписанное со свежей меткой
+	if code := serve(handler, signedRequest(testBody, now)); code != http.StatusConflict {
+		t.Fatalf("replay status = %d, want 409", code)
+	}
+	if code := serve(handler, signedRequest(testBody, now+1)); code != http.StatusConflict {
+		t.Fatalf("replay with new timestamp status = %d, want 409", code)
+	}
+	if len(shop.results) != 1 {
+		t.Fatalf("ConfirmPayment called %d times, want 1", len(shop.results))
+	}
+}
+
+func TestWebhookReleasesEventOnTemporaryError(t *testing.T) {
+	handler, shop := newTestHandler()
+	shop.err = errors.New("database is down")
+
+	if code := serve(handler, signedRequest(testBody, time.Now().Unix())); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", code)
+	}
+	//Провайдер повторяет событие, и теперь оно проходит
+	shop.err = nil
+	if code := serve(handler, signedRequest(testBody, time.Now().Unix())); code != http.StatusNoContent {
+		t.Fatalf("retry status = %d, want 204", code)
+	}
+	if len(shop.results) != 2 {
+		t.Fatalf("ConfirmPayment called %d times, want 2", len(shop.results))
+	}
+}
+
+func TestWebhookKeepsEventOnFinalError(t *testing.T) {
+	handler, shop := newTestHandler()
+	shop.err = models.ErrInvalidTransition
+
+	if code := serve(handler, signedRequest(testBody, time.Now().Unix())); code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", code)
+	}
+	shop.err = nil
+	if code := serve(handler, signedRequest(testBody, time.Now().Unix())); code != http.StatusConflict {
+		t.Fatalf("repeat status = %d, want 409", code)
+	}
+	if len(shop.results) != 1 {
+		t.Fatalf("ConfirmPayment called %d times, want 1", len(shop.results))
+	}
+}
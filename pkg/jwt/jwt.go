@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Claims - данные, которые сервисы получают из проверенного access-токена
+type Claims struct {
+	UserID    int64
+	Email     string
+	SessionID string
+	Roles     []models.Role
+	KeyID     string
+	ExpiresAt time.Time
+}
+
+type tokenClaims struct {
+	UserID    int64         `json:"uid"`
+	Email     string        `json:"email"`
+	SessionID string        `json:"sid"`
+	Roles     []models.Role `json:"roles,omitempty"`
+	gojwt.RegisteredClaims
+}
+
+// Issuer подписывает access-токены текущим ключом из конфига
+type Issuer struct {
+	keyID  string
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewIssuer(keyID, secret string, ttl time.Duration) *Issuer {
+	return &Issuer{
+		keyID:  keyID,
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+func (i *Issuer) NewAccessToken(user models.User, sessionID string) (token string, expiresAt time.Time, err error) {
+	const op = "jwt.NewAccessToken"
+
+	now := time.Now()
+	expiresAt = now.Add(i.ttl)
+
+	t := gojwt.NewWithClaims(gojwt.SigningMethodHS256, tokenClaims{
+		UserID:    user.UserID,
+		Email:     user.Email,
+		SessionID: sessionID,
+		Roles:     user.Roles,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			IssuedAt:  gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(expiresAt),
+		},
+	})
+	t.Header["kid"] = i.keyID
+
+	token, err = t.SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return token, expiresAt, nil
+}
+
+// Verifier проверяет подпись и срок действия токенов.
+// Ключи ищутся по kid, поэтому после ротации старые токены продолжают работать,
+// пока их ключ остается в списке.
+type Verifier struct {
+	keys map[string][]byte
+}
+
+func NewVerifier(keys map[string]string) *Verifier {
+	v := &Verifier{keys: make(map[string][]byte, len(keys))}
+	for kid, secret := range keys {
+		v.keys[kid] = []byte(secret)
+	}
+	return v
+}
+
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	const op = "jwt.Verify"
+
+	var claims tokenClaims
+	t, err := gojwt.ParseWithClaims(token, &claims, v.keyFunc,
+		gojwt.WithValidMethods([]string{gojwt.SigningMethodHS256.Alg()}),
+		gojwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, gojwt.ErrTokenExpired):
+			return nil, fmt.Errorf("%s: %w", op, ErrTokenExpired)
+		case errors.Is(err, ErrUnknownKey):
+			return nil, fmt.Errorf("%s: %w", op, ErrUnknownKey)
+		default:
+			return nil, fmt.Errorf("%s: %w: %s", op, ErrInvalidToken, err)
+		}
+	}
+	if claims.UserID <= 0 || claims.SessionID == "" {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	return &Claims{
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		SessionID: claims.SessionID,
+		Roles:     claims.Roles,
+		KeyID:     kid,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (v *Verifier) keyFunc(t *gojwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	secret, ok := v.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return secret, nil
+}
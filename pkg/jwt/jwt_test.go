@@ -0,0 +1,181 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+const (
+	testKeyID  = "v2"
+	testSecret = "current-secret"
+)
+
+var testUser = models.User{
+	UserID: 7,
+	Email:  "user@example.com",
+	Roles:  []models.Role{models.RoleCustomer, models.RoleSupport},
+}
+
+func newTestVerifier() *Verifier {
+	return NewVerifier(map[string]string{
+		"v1":      "previous-secret",
+		testKeyID: testSecret,
+	})
+}
+
+// signClaims подписывает произвольные claims, чтобы собрать токены, которые Issuer не выпускает
+func signClaims(t *testing.T, method gojwt.SigningMethod, kid string, key any, claims tokenClaims) string {
+	t.Helper()
+
+	token := gojwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign %s token: %v", method.Alg(), err)
+	}
+	return signed
+}
+
+func validClaims() tokenClaims {
+	now := time.Now()
+	return tokenClaims{
+		UserID:    testUser.UserID,
+		Email:     testUser.Email,
+		SessionID: "session",
+		RegisteredClaims: gojwt.RegisteredClaims{
+			IssuedAt:  gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	token, expiresAt, err := NewIssuer(testKeyID, testSecret, time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+
+	claims, err := newTestVerifier().Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.UserID != testUser.UserID || claims.Email != testUser.Email || claims.SessionID != "session" {
+		t.Fatalf("claims = %+v", claims)
+	}
+	if claims.KeyID != testKeyID {
+		t.Fatalf("KeyID = %q, want %q", claims.KeyID, testKeyID)
+	}
+	if len(claims.Roles) != 2 || claims.Roles[0] != models.RoleCustomer || claims.Roles[1] != models.RoleSupport {
+		t.Fatalf("Roles = %v", claims.Roles)
+	}
+	//В токене время хранится с точностью до секунды
+	if !claims.ExpiresAt.Equal(expiresAt.Truncate(time.Second)) {
+		t.Fatalf("ExpiresAt = %v, want %v", claims.ExpiresAt, expiresAt)
+	}
+}
+
+func TestVerifyAcceptsRotatedKey(t *testing.T) {
+	token, _, err := NewIssuer("v1", "previous-secret", time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	claims, err := newTestVerifier().Verify(token)
+	if err != nil {
+		t.Fatalf("Verify with previous key: %v", err)
+	}
+	if claims.KeyID != "v1" {
+		t.Fatalf("KeyID = %q, want v1", claims.KeyID)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	expired, _, err := NewIssuer(testKeyID, testSecret, -time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	wrongSecret, _, err := NewIssuer(testKeyID, "other-secret", time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	unknownKid, _, err := NewIssuer("v9", testSecret, time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	//Старый ключ не подходит к токену с kid текущего
+	swappedKid, _, err := NewIssuer(testKeyID, "previous-secret", time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	valid, _, err := NewIssuer(testKeyID, testSecret, time.Minute).NewAccessToken(testUser, "session")
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+
+	//Подменяем payload, оставляя подпись исходного токена
+	parts := strings.Split(valid, ".")
+	forged := signClaims(t, gojwt.SigningMethodHS256, testKeyID, []byte("attacker"), func() tokenClaims {
+		c := validClaims()
+		c.Roles = []models.Role{models.RoleAdmin}
+		return c
+	}())
+	tampered := parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+	noSession := validClaims()
+	noSession.SessionID = ""
+	noUser := validClaims()
+	noUser.UserID = 0
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "expired", token: expired, wantErr: ErrTokenExpired},
+		{name: "wrong signature", token: wrongSecret, wantErr: ErrInvalidToken},
+		{name: "tampered payload", token: tampered, wantErr: ErrInvalidToken},
+		{name: "unknown kid", token: unknownKid, wantErr: ErrUnknownKey},
+		{name: "kid of another key", token: swappedKid, wantErr: ErrInvalidToken},
+		{name: "missing kid", token: signClaims(t, gojwt.SigningMethodHS256, "", []byte(testSecret), validClaims()), wantErr: ErrUnknownKey},
+		{
+			name:    "alg none",
+			token:   signClaims(t, gojwt.SigningMethodNone, testKeyID, gojwt.UnsafeAllowNoneSignatureType, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "alg switch to HS512",
+			token:   signClaims(t, gojwt.SigningMethodHS512, testKeyID, []byte(testSecret), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "alg switch to HS384",
+			token:   signClaims(t, gojwt.SigningMethodHS384, testKeyID, []byte(testSecret), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{name: "no expiry", token: signClaims(t, gojwt.SigningMethodHS256, testKeyID, []byte(testSecret), noExpiry), wantErr: ErrInvalidToken},
+		{name: "no session", token: signClaims(t, gojwt.SigningMethodHS256, testKeyID, []byte(testSecret), noSession), wantErr: ErrInvalidToken},
+		{name: "no user", token: signClaims(t, gojwt.SigningMethodHS256, testKeyID, []byte(testSecret), noUser), wantErr: ErrInvalidToken},
+		{name: "garbage", token: "not.a.token", wantErr: ErrInvalidToken},
+		{name: "empty", token: "", wantErr: ErrInvalidToken},
+	}
+	verifier := newTestVerifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+			if claims != nil {
+				t.Fatalf("Verify returned claims %+v for rejected token", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package fakepayment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/http/paymenthttp"
+)
+
+// webhookTimeout - сколько страница оплаты ждет ответа магазина на вебхук
+const webhookTimeout = 5 * time.Second
+
+// Checkout - страница оплаты fake-провайдера. GET показывает сессию, POST с result=succeeded|failed
+// закрывает ее через Complete и, как настоящий PSP, присылает магазину подписанный вебхук.
+// Так весь путь заказ -> оплата -> вебхук -> возврат проходит локально без настоящего провайдера
+type Checkout struct {
+	log        *slog.Logger
+	provider   *Provider
+	webhookURL string
+	secret     []byte
+	client     *http.Client
+}
+
+func NewCheckout(log *slog.Logger, provider *Provider, webhookURL, secret string) *Checkout {
+	return &Checkout{
+		log:        log,
+		provider:   provider,
+		webhookURL: webhookURL,
+		secret:     []byte(secret),
+		client:     &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+var checkoutPage = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Fake payment {{.ExternalID}}</title></head>
+<body>
+<h1>Order #{{.OrderID}}</h1>
+<p>Amount: {{.Amount}}</p>
+<p>Status: {{.Status}}</p>
+{{if eq .Status "pending"}}
+<form method="post"><button name="result" value="succeeded">Pay</button></form>
+<form method="post"><button name="result" value="failed">Decline</button></form>
+{{end}}
+</body>
+</html>
+`))
+
+func (c *Checkout) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "payments.fakepayment.Checkout"
+
+	externalID := path.Base(r.URL.Path)
+	log := c.log.With(slog.String("operation", op), slog.String("payment_id", externalID))
+
+	switch r.Method {
+	case http.MethodGet:
+		session, err := c.provider.session(externalID)
+		if err != nil {
+			http.Error(w, "payment not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := checkoutPage.Execute(w, session); err != nil {
+			log.Error("Failed to render checkout page", slog.String("error", err.Error()))
+		}
+	case http.MethodPost:
+		var success bool
+		switch r.FormValue("result") {
+		case string(models.PaymentStatusSucceeded):
+			success = true
+		case string(models.PaymentStatusFailed):
+		default:
+			http.Error(w, "result must be succeeded or failed", http.StatusBadRequest)
+			return
+		}
+
+		session, err := c.provider.Complete(externalID, success)
+		switch {
+		case errors.Is(err, models.ErrPaymentNotFound):
+			http.Error(w, "payment not found", http.StatusNotFound)
+			return
+		case errors.Is(err, models.ErrInvalidTransition):
+			http.Error(w, "payment is already completed", http.StatusConflict)
+			return
+		case err != nil:
+			log.Error("Failed to complete payment", slog.String("error", err.Error()))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		if err := c.notify(r.Context(), *session); err != nil {
+			//Сессия у провайдера уже закрыта, но магазин об этом не узнал
+			log.Error("Failed to deliver webhook", slog.String("error", err.Error()))
+			http.Error(w, "failed to notify shop", http.StatusBadGateway)
+			return
+		}
+		log.Info("Payment completed", slog.String("status", string(session.Status)))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := checkoutPage.Execute(w, session); err != nil {
+			log.Error("Failed to render checkout page", slog.String("error", err.Error()))
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// notify отправляет магазину подписанный вебхук об итоге оплаты
+func (c *Checkout) notify(ctx context.Context, session models.PaymentSession) error {
+	eventID, err := newExternalID()
+	if err != nil {
+		return err
+	}
+	eventType := paymenthttp.EventPaymentFailed
+	if session.Status == models.PaymentStatusSucceeded {
+		eventType = paymenthttp.EventPaymentSucceeded
+	}
+	body, err := json.Marshal(paymenthttp.WebhookEvent{
+		ID:        "evt_" + eventID,
+		Type:      eventType,
+		OrderID:   session.OrderID,
+		PaymentID: session.ExternalID,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(paymenthttp.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(paymenthttp.SignatureHeader, paymenthttp.Sign(c.secret, timestamp, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
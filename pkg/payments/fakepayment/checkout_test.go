@@ -0,0 +1,140 @@
+package fakepayment
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/http/paymenthttp"
+)
+
+const testSecret = "test-secret"
+
+type recordingShop struct {
+	mu      sync.Mutex
+	results []models.PaymentResult
+}
+
+func (s *recordingShop) ConfirmPayment(_ context.Context, result models.PaymentResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	return nil
+}
+
+type memoryEvents struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (e *memoryEvents) SaveWebhookEvent(_ context.Context, _, eventID string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.seen[eventID] {
+		return false, nil
+	}
+	e.seen[eventID] = true
+	return true, nil
+}
+
+func (e *memoryEvents) ReleaseWebhookEvent(_ context.Context, _, eventID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.seen, eventID)
+	return nil
+}
+
+// newCheckoutServer поднимает страницу оплаты и настоящий обработчик вебхуков магазина
+func newCheckoutServer(t *testing.T) (*Provider, *recordingShop) {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	shop := &recordingShop{}
+	webhook := paymenthttp.NewWebhookHandler(log, shop, &memoryEvents{seen: make(map[string]bool)}, ProviderName, testSecret, time.Minute)
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/payment", webhook)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := New(server.URL + "/pay")
+	mux.Handle("/pay/", NewCheckout(log, provider, server.URL+"/webhooks/payment", testSecret))
+	return provider, shop
+}
+
+func TestCheckoutDeliversSignedWebhook(t *testing.T) {
+	provider, shop := newCheckoutServer(t)
+	session, err := provider.CreatePayment(context.Background(), testOrder(42, 1500))
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	page, err := http.Get(session.URL)
+	if err != nil {
+		t.Fatalf("GET checkout: %v", err)
+	}
+	page.Body.Close()
+	if page.StatusCode != http.StatusOK {
+		t.Fatalf("GET checkout status = %d", page.StatusCode)
+	}
+
+	resp, err := http.PostForm(session.URL, url.Values{"result": {"succeeded"}})
+	if err != nil {
+		t.Fatalf("POST checkout: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST checkout status = %d: %s", resp.StatusCode, body)
+	}
+
+	if len(shop.results) != 1 {
+		t.Fatalf("shop received %d webhooks, want 1", len(shop.results))
+	}
+	got := shop.results[0]
+	if got.OrderID != 42 || got.ExternalID != session.ExternalID || !got.Success {
+		t.Fatalf("payment result = %+v", got)
+	}
+
+	//После оплаты через страницу деньги можно вернуть
+	if _, err := provider.Refund(context.Background(), session.ExternalID, models.Money{Amount: 1500, Currency: "USD"}); err != nil {
+		t.Fatalf("Refund after checkout: %v", err)
+	}
+
+	again, err := http.PostForm(session.URL, url.Values{"result": {"failed"}})
+	if err != nil {
+		t.Fatalf("repeat POST checkout: %v", err)
+	}
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Fatalf("repeat POST status = %d, want 409", again.StatusCode)
+	}
+}
+
+func TestCheckoutRejectsUnknownResult(t *testing.T) {
+	provider, shop := newCheckoutServer(t)
+	session, err := provider.CreatePayment(context.Background(), testOrder(7, 100))
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	resp, err := http.Post(session.URL, "application/x-www-form-urlencoded", strings.NewReader("result=maybe"))
+	if err != nil {
+		t.Fatalf("POST checkout: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	if len(shop.results) != 0 {
+		t.Fatalf("shop received %d webhooks, want 0", len(shop.results))
+	}
+}
@@ -0,0 +1,179 @@
+package fakepayment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+// ProviderName - имя провайдера в конфиге и в данных о платеже
+const ProviderName = "fake"
+
+// sessionTTL - сколько живет платежная сессия, как у типичного PSP
+const sessionTTL = time.Hour
+
+// Provider - платежный провайдер в памяти процесса для локальной разработки и тестов.
+// Деньги никуда не списываются: сессия остается pending, пока ее не закроют через Complete
+// на странице оплаты Checkout
+type Provider struct {
+	baseURL string
+
+	mu       sync.Mutex
+	sessions map[string]*payment
+	byOrder  map[int64]string
+}
+
+type payment struct {
+	session  models.PaymentSession
+	refunded models.Money
+}
+
+func New(baseURL string) *Provider {
+	return &Provider{
+		baseURL:  baseURL,
+		sessions: make(map[string]*payment),
+		byOrder:  make(map[int64]string),
+	}
+}
+
+func (p *Provider) CreatePayment(_ context.Context, order models.Order) (*models.PaymentSession, error) {
+	const op = "payments.fakepayment.CreatePayment"
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	//Сессия по заказу уже открыта - отдаем ее же
+	if id, ok := p.byOrder[order.ID]; ok {
+		session := p.sessions[id].session
+		return &session, nil
+	}
+
+	id, err := newExternalID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	paymentURL, err := url.JoinPath(p.baseURL, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	session := models.PaymentSession{
+		Provider:   ProviderName,
+		ExternalID: id,
+		OrderID:    order.ID,
+		URL:        paymentURL,
+		Amount:     order.Sum,
+		Status:     models.PaymentStatusPending,
+		ExpiresAt:  time.Now().Add(sessionTTL),
+	}
+	//Имитируем ответ настоящего провайдера, который сохранится в истории платежа
+	session.RawPayload, err = json.Marshal(map[string]any{
+		"id":       id,
+		"order_id": order.ID,
+		"amount":   order.Sum.Amount,
+		"currency": order.Sum.Currency,
+		"url":      paymentURL,
+		"status":   session.Status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	p.sessions[id] = &payment{session: session, refunded: models.Money{Currency: order.Sum.Currency}}
+	p.byOrder[order.ID] = id
+	return &session, nil
+}
+
+func (p *Provider) PaymentStatus(_ context.Context, externalID string) (models.PaymentStatus, error) {
+	const op = "payments.fakepayment.PaymentStatus"
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.sessions[externalID]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", op, models.ErrPaymentNotFound)
+	}
+	return pay.session.Status, nil
+}
+
+// Refund возвращает часть или всю сумму оплаченной сессии
+func (p *Provider) Refund(_ context.Context, externalID string, amount models.Money) (*models.ProviderRefund, error) {
+	const op = "payments.fakepayment.Refund"
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.sessions[externalID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrPaymentNotFound)
+	}
+	if pay.session.Status != models.PaymentStatusSucceeded && pay.session.Status != models.PaymentStatusRefunded {
+		return nil, fmt.Errorf("%s: payment is %s: %w", op, pay.session.Status, models.ErrInvalidTransition)
+	}
+	refunded, err := pay.refunded.Add(amount)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if amount.Amount <= 0 || refunded.Amount > pay.session.Amount.Amount {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrRefundExceedsAmount)
+	}
+	pay.refunded = refunded
+	if refunded.Amount == pay.session.Amount.Amount {
+		pay.session.Status = models.PaymentStatusRefunded
+	}
+
+	id, err := newExternalID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &models.ProviderRefund{ExternalID: id, Amount: amount, Status: models.RefundStatusSucceeded}, nil
+}
+
+// Complete имитирует действие покупателя на странице оплаты: успешную или неуспешную оплату
+func (p *Provider) Complete(externalID string, success bool) (*models.PaymentSession, error) {
+	const op = "payments.fakepayment.Complete"
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.sessions[externalID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrPaymentNotFound)
+	}
+	if pay.session.Status != models.PaymentStatusPending {
+		return nil, fmt.Errorf("%s: payment is %s: %w", op, pay.session.Status, models.ErrInvalidTransition)
+	}
+	pay.session.Status = models.PaymentStatusFailed
+	if success {
+		pay.session.Status = models.PaymentStatusSucceeded
+	}
+	session := pay.session
+	return &session, nil
+}
+
+// session - копия сессии для страницы оплаты
+func (p *Provider) session(externalID string) (*models.PaymentSession, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pay, ok := p.sessions[externalID]
+	if !ok {
+		return nil, models.ErrPaymentNotFound
+	}
+	session := pay.session
+	return &session, nil
+}
+
+func newExternalID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "fake_" + hex.EncodeToString(buf), nil
+}
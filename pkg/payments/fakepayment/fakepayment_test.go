@@ -0,0 +1,97 @@
+package fakepayment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+func testOrder(id, amount int64) models.Order {
+	return models.Order{ID: id, Sum: models.Money{Amount: amount, Currency: "USD"}}
+}
+
+func TestCreatePaymentIsIdempotentPerOrder(t *testing.T) {
+	p := New("http://localhost:8081/pay")
+
+	first, err := p.CreatePayment(context.Background(), testOrder(1, 1000))
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+	second, err := p.CreatePayment(context.Background(), testOrder(1, 1000))
+	if err != nil {
+		t.Fatalf("CreatePayment again: %v", err)
+	}
+	if first.ExternalID != second.ExternalID {
+		t.Fatalf("second session %q, want %q", second.ExternalID, first.ExternalID)
+	}
+	if want := "http://localhost:8081/pay/" + first.ExternalID; first.URL != want {
+		t.Fatalf("URL = %q, want %q", first.URL, want)
+	}
+	if first.Status != models.PaymentStatusPending {
+		t.Fatalf("status = %s, want pending", first.Status)
+	}
+}
+
+func TestCompleteChangesStatusOnce(t *testing.T) {
+	p := New("http://localhost:8081/pay")
+	session, err := p.CreatePayment(context.Background(), testOrder(1, 1000))
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	if _, err := p.Complete(session.ExternalID, true); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	status, err := p.PaymentStatus(context.Background(), session.ExternalID)
+	if err != nil {
+		t.Fatalf("PaymentStatus: %v", err)
+	}
+	if status != models.PaymentStatusSucceeded {
+		t.Fatalf("status = %s, want succeeded", status)
+	}
+	if _, err := p.Complete(session.ExternalID, false); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Fatalf("second Complete error = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := p.Complete("fake_missing", true); !errors.Is(err, models.ErrPaymentNotFound) {
+		t.Fatalf("Complete of unknown session error = %v, want ErrPaymentNotFound", err)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	ctx := context.Background()
+	p := New("http://localhost:8081/pay")
+	session, err := p.CreatePayment(ctx, testOrder(1, 1000))
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	if _, err := p.Refund(ctx, session.ExternalID, models.Money{Amount: 100, Currency: "USD"}); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Fatalf("refund of pending payment error = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := p.Complete(session.ExternalID, true); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	refund, err := p.Refund(ctx, session.ExternalID, models.Money{Amount: 400, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("partial Refund: %v", err)
+	}
+	if refund.Status != models.RefundStatusSucceeded || refund.Amount.Amount != 400 {
+		t.Fatalf("refund = %+v, want succeeded 400", refund)
+	}
+	if _, err := p.Refund(ctx, session.ExternalID, models.Money{Amount: 700, Currency: "USD"}); !errors.Is(err, models.ErrRefundExceedsAmount) {
+		t.Fatalf("over-refund error = %v, want ErrRefundExceedsAmount", err)
+	}
+	if _, err := p.Refund(ctx, session.ExternalID, models.Money{Amount: 600, Currency: "USD"}); err != nil {
+		t.Fatalf("remaining Refund: %v", err)
+	}
+	status, err := p.PaymentStatus(ctx, session.ExternalID)
+	if err != nil {
+		t.Fatalf("PaymentStatus: %v", err)
+	}
+	if status != models.PaymentStatusRefunded {
+		t.Fatalf("status = %s, want refunded", status)
+	}
+}
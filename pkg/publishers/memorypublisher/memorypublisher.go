@@ -0,0 +1,64 @@
+package memorypublisher
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+// PublisherName - имя публикатора в конфиге
+const PublisherName = "log"
+
+// DefaultCapacity - сколько последних событий помнит публикатор сервиса
+const DefaultCapacity = 1000
+
+// Publisher пишет события в лог и помнит последние capacity из них в кольцевом буфере.
+// Нужен для локальной разработки и тестов, когда внешнего брокера нет; память не растет,
+// сколько бы событий ни прошло через долгоживущий сервер
+type Publisher struct {
+	log *slog.Logger
+
+	mu     sync.Mutex
+	events []models.Event
+	//next - позиция следующей записи, когда буфер уже заполнен
+	next int
+}
+
+func New(log *slog.Logger, capacity int) *Publisher {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Publisher{log: log, events: make([]models.Event, 0, capacity)}
+}
+
+func (p *Publisher) Publish(_ context.Context, event models.Event) error {
+	p.mu.Lock()
+	if len(p.events) < cap(p.events) {
+		p.events = append(p.events, event)
+	} else {
+		p.events[p.next] = event
+		p.next = (p.next + 1) % len(p.events)
+	}
+	p.mu.Unlock()
+
+	p.log.Info("event published",
+		slog.Int64("event_id", event.ID),
+		slog.String("type", event.Type),
+		slog.String("aggregate_type", event.AggregateType),
+		slog.Int64("aggregate_id", event.AggregateID),
+		slog.String("payload", string(event.Payload)),
+	)
+	return nil
+}
+
+// Events возвращает копию последних опубликованных событий в порядке публикации
+func (p *Publisher) Events() []models.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]models.Event, 0, len(p.events))
+	events = append(events, p.events[p.next:]...)
+	return append(events, p.events[:p.next]...)
+}
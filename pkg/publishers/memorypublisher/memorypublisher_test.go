@@ -0,0 +1,60 @@
+package memorypublisher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+func publish(t *testing.T, p *Publisher, ids ...int64) {
+	t.Helper()
+	for _, id := range ids {
+		if err := p.Publish(context.Background(), models.Event{ID: id}); err != nil {
+			t.Fatalf("Publish(%d): %v", id, err)
+		}
+	}
+}
+
+func eventIDs(events []models.Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEventsKeepPublishOrder(t *testing.T) {
+	p := New(slog.New(slog.NewTextHandler(io.Discard, nil)), 5)
+	publish(t, p, 1, 2, 3)
+
+	if got, want := eventIDs(p.Events()), []int64{1, 2, 3}; !equalIDs(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestEventsAreBounded(t *testing.T) {
+	p := New(slog.New(slog.NewTextHandler(io.Discard, nil)), 3)
+	publish(t, p, 1, 2, 3, 4, 5, 6, 7)
+
+	if got, want := eventIDs(p.Events()), []int64{5, 6, 7}; !equalIDs(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if cap(p.events) != 3 {
+		t.Fatalf("buffer capacity = %d, want 3", cap(p.events))
+	}
+}
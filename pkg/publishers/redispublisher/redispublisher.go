@@ -0,0 +1,59 @@
+package redispublisher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+// PublisherName - имя публикатора в конфиге
+const PublisherName = "redis"
+
+// Publisher добавляет события в Redis Stream. Все события идут в один поток,
+// поэтому потребители получают события заказа в том порядке, в каком их отправил relay.
+// Повторно отправленное событие придет с тем же event_id
+type Publisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func New(addr, password string, db int, stream string, maxLen int64) (*Publisher, error) {
+	const op = "publishers.redispublisher.New"
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Publisher{client: rdb, stream: stream, maxLen: maxLen}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, event models.Event) error {
+	const op = "publishers.redispublisher.Publish"
+
+	err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"event_id":       strconv.FormatInt(event.ID, 10),
+			"type":           event.Type,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   strconv.FormatInt(event.AggregateID, 10),
+			"payload":        string(event.Payload),
+			"created_at":     event.CreatedAt.Format(time.RFC3339Nano),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
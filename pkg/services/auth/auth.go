@@ -2,40 +2,77 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"time"
 )
 
 type Auth struct {
 	log         *slog.Logger
 	usrsaver    UserSaver
 	usrprovider UserProvider
+	tokens      TokenIssuer
+	sessions    SessionStorage
+	refreshTTL  time.Duration
+	adminEmails map[string]bool
 }
 
 type UserSaver interface {
-	SaveUser(ctx context.Context, email string, passhash []byte) (uid int64, err error)
+	SaveUser(ctx context.Context, email string, passhash []byte, roles []models.Role) (uid int64, err error)
+	SetUserRoles(ctx context.Context, uid int64, roles []models.Role) error
 }
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, uid int64) (models.User, error)
+}
+
+type TokenIssuer interface {
+	NewAccessToken(user models.User, sessionID string) (token string, expiresAt time.Time, err error)
+}
+
+type SessionStorage interface {
+	CreateSession(ctx context.Context, session models.Session) error
+	SessionActive(ctx context.Context, sessionID string) (bool, error)
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	UseRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUserSessions(ctx context.Context, uid int64) (int, error)
 }
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 )
 
 func New(
 	log *slog.Logger,
 	usrsaver UserSaver,
 	usprovider UserProvider,
+	tokens TokenIssuer,
+	sessions SessionStorage,
+	refreshTTL time.Duration,
+	adminEmails []string,
 ) *Auth {
+	admins := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		admins[email] = true
+	}
 	return &Auth{
 		log:         log,
 		usrsaver:    usrsaver,
 		usrprovider: usprovider,
+		tokens:      tokens,
+		sessions:    sessions,
+		refreshTTL:  refreshTTL,
+		adminEmails: admins,
 	}
 }
 
@@ -48,24 +85,30 @@ func (a *Auth) RegisterNewUser(ctx context.Context, email, password string) (use
 
 	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Error("Failed to hash password", slog.StringValue(err.Error()))
+		log.Error("Failed to hash password", slog.String("error", err.Error()))
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	id, err := a.usrsaver.SaveUser(ctx, email, passHash)
+	//Адреса из конфига получают роль администратора при регистрации
+	roles := []models.Role{models.RoleCustomer}
+	if a.adminEmails[email] {
+		roles = append(roles, models.RoleAdmin)
+	}
+
+	id, err := a.usrsaver.SaveUser(ctx, email, passHash, roles)
 	if err != nil {
 		if errors.Is(err, models.ErrUserExists) {
-			a.log.Warn("User already exists", slog.StringValue(err.Error()))
+			a.log.Warn("User already exists", slog.String("error", err.Error()))
 			return 0, fmt.Errorf("%s: %w", op, models.ErrUserExists)
 		}
-		log.Error("Failed to save user", slog.StringValue(err.Error()))
+		log.Error("Failed to save user", slog.String("error", err.Error()))
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 	log.Info("user registered")
 	return id, nil
 }
 
-func (a *Auth) LoginUser(ctx context.Context, email, password string) (success bool, err error) {
+func (a *Auth) LoginUser(ctx context.Context, email, password string) (models.Tokens, error) {
 	const op = "auth.LoginUser"
 
 	log := a.log.With(slog.String("operation", op), slog.String("email", email))
@@ -75,16 +118,200 @@ func (a *Auth) LoginUser(ctx context.Context, email, password string) (success b
 	usr, err := a.usrprovider.User(ctx, email)
 	if err != nil {
 		if errors.Is(err, models.ErrUserNotFound) {
-			a.log.Warn("User not found", slog.StringValue(err.Error()))
-			return false, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+			a.log.Warn("User not found", slog.String("error", err.Error()))
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
-		log.Error("Failed to get user", slog.StringValue(err.Error()))
-		return false, fmt.Errorf("%s: %w", op, err)
+		log.Error("Failed to get user", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
 	}
 	if err := bcrypt.CompareHashAndPassword(usr.Passhash, []byte(password)); err != nil {
-		a.log.Warn("Invalid credentials", slog.StringValue(err.Error()))
-		return false, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		a.log.Warn("Invalid credentials", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	sessionID, err := randomToken(16)
+	if err != nil {
+		log.Error("Failed to generate session id", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
 	}
+	now := time.Now()
+	err = a.sessions.CreateSession(ctx, models.Session{
+		ID:        sessionID,
+		UserID:    usr.UserID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.refreshTTL),
+	})
+	if err != nil {
+		log.Error("Failed to create session", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := a.issueTokens(ctx, usr, sessionID)
+	if err != nil {
+		log.Error("Failed to issue tokens", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("user logged in")
-	return true, nil
+	return tokens, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов.
+// Каждый refresh-токен одноразовый: при повторном предъявлении
+// отзывается вся сессия, включая уже выданный взамен токен.
+func (a *Auth) Refresh(ctx context.Context, refreshToken string) (models.Tokens, error) {
+	const op = "auth.Refresh"
+
+	log := a.log.With(slog.String("operation", op))
+
+	log.Info("attempting to refresh tokens")
+
+	stored, err := a.sessions.UseRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRefreshTokenNotFound):
+			log.Warn("Refresh token not found", slog.String("error", err.Error()))
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		case errors.Is(err, models.ErrRefreshTokenReused):
+			log.Warn("Refresh token reuse detected, revoking session",
+				slog.Int64("user_id", stored.UserID),
+				slog.String("session_id", stored.SessionID),
+			)
+			if err := a.sessions.RevokeSession(ctx, stored.SessionID); err != nil {
+				log.Error("Failed to revoke session", slog.String("error", err.Error()))
+				return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+			}
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		log.Error("Failed to use refresh token", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	active, err := a.sessions.SessionActive(ctx, stored.SessionID)
+	if err != nil {
+		log.Error("Failed to check session", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !active {
+		log.Warn("Session revoked", slog.String("session_id", stored.SessionID))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	usr, err := a.usrprovider.UserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			log.Warn("User not found", slog.String("error", err.Error()))
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		log.Error("Failed to get user", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := a.issueTokens(ctx, usr, stored.SessionID)
+	if err != nil {
+		log.Error("Failed to issue tokens", slog.String("error", err.Error()))
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tokens refreshed", slog.Int64("user_id", usr.UserID))
+	return tokens, nil
+}
+
+// SetUserRoles заменяет набор ролей пользователя.
+// Методы с проверкой ролей видят новые роли сразу, остальные - из access-токена,
+// куда они попадут при следующем входе или обновлении токенов.
+func (a *Auth) SetUserRoles(ctx context.Context, userID int64, roles []models.Role) error {
+	const op = "auth.SetUserRoles"
+
+	log := a.log.With(slog.String("operation", op), slog.Int64("user_id", userID))
+
+	for _, r := range roles {
+		if !r.Valid() {
+			log.Warn("Invalid role", slog.String("role", string(r)))
+			return fmt.Errorf("%s: %w", op, models.ErrInvalidRole)
+		}
+	}
+
+	if err := a.usrsaver.SetUserRoles(ctx, userID, roles); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			log.Warn("User not found", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, models.ErrUserNotFound)
+		}
+		log.Error("Failed to set roles", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("user roles updated")
+	return nil
+}
+
+// Logout завершает одну сессию
+func (a *Auth) Logout(ctx context.Context, sessionID string) error {
+	const op = "auth.Logout"
+
+	log := a.log.With(slog.String("operation", op), slog.String("session_id", sessionID))
+
+	if err := a.sessions.RevokeSession(ctx, sessionID); err != nil {
+		log.Error("Failed to revoke session", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("session revoked")
+	return nil
+}
+
+// LogoutAll завершает все сессии пользователя
+func (a *Auth) LogoutAll(ctx context.Context, userID int64) (int, error) {
+	const op = "auth.LogoutAll"
+
+	log := a.log.With(slog.String("operation", op), slog.Int64("user_id", userID))
+
+	revoked, err := a.sessions.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		log.Error("Failed to revoke sessions", slog.String("error", err.Error()))
+		return revoked, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("all sessions revoked", slog.Int("count", revoked))
+	return revoked, nil
+}
+
+func (a *Auth) issueTokens(ctx context.Context, usr models.User, sessionID string) (models.Tokens, error) {
+	accessToken, accessExpiresAt, err := a.tokens.NewAccessToken(usr, sessionID)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+	refreshExpiresAt := time.Now().Add(a.refreshTTL)
+
+	err = a.sessions.SaveRefreshToken(ctx, models.RefreshToken{
+		Hash:      hashToken(refreshToken),
+		UserID:    usr.UserID,
+		SessionID: sessionID,
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	return models.Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
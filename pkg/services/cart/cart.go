@@ -0,0 +1,259 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+)
+
+type Cart struct {
+	log      *slog.Logger
+	storage  CartStorage
+	products ProductProvider
+	orders   OrderMaker
+}
+
+type CartStorage interface {
+	AddItem(ctx context.Context, userID, productID, variantID int64, quantity, maxQuantity int32) error
+	SetQuantity(ctx context.Context, userID, productID, variantID int64, quantity, maxQuantity int32) error
+	RemoveItem(ctx context.Context, userID, productID, variantID int64) error
+	Items(ctx context.Context, userID int64) ([]models.CartItem, error)
+	Clear(ctx context.Context, userID int64) error
+}
+
+type ProductProvider interface {
+	Product(ctx context.Context, productID int64) (*models.Product, error)
+}
+
+// OrderMaker - оформление заказа через обычный путь резервации магазина
+type OrderMaker interface {
+	MakeOrder(ctx context.Context, userID int64, items []models.OrderItem) (*models.Order, error)
+}
+
+func New(log *slog.Logger, storage CartStorage, products ProductProvider, orders OrderMaker) *Cart {
+	return &Cart{
+		log:      log,
+		storage:  storage,
+		products: products,
+		orders:   orders,
+	}
+}
+
+func (c *Cart) AddItem(ctx context.Context, userID, productID, variantID int64, quantity int32) (*models.Cart, error) {
+	const op = "cart.AddItem"
+
+	log := c.log.With(
+		slog.String("operation", op),
+		slog.Int64("userID", userID),
+		slog.Int64("productID", productID),
+	)
+
+	//Не даем положить в корзину несуществующий товар или вариант
+	product, err := c.products.Product(ctx, productID)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			log.Warn("Product not found")
+			return nil, fmt.Errorf("%s: %w", op, models.ErrProductNotFound)
+		}
+		log.Error("Failed to get product", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	maxQuantity, err := itemLimit(*product, variantID)
+	if err != nil {
+		log.Warn("Cannot add item", slog.Int64("variantID", variantID), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.storage.AddItem(ctx, userID, productID, variantID, quantity, maxQuantity); err != nil {
+		if errors.Is(err, models.ErrCartQuantityExceeded) {
+			log.Warn("Cart quantity exceeded", slog.Int("max", int(maxQuantity)))
+			return nil, fmt.Errorf("%s: %w", op, models.ErrCartQuantityExceeded)
+		}
+		log.Error("Failed to add item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("item added to cart")
+	return c.GetCart(ctx, userID)
+}
+
+func (c *Cart) UpdateQuantity(ctx context.Context, userID, productID, variantID int64, quantity int32) (*models.Cart, error) {
+	const op = "cart.UpdateQuantity"
+
+	if quantity == 0 {
+		return c.RemoveItem(ctx, userID, productID, variantID)
+	}
+
+	log := c.log.With(
+		slog.String("operation", op),
+		slog.Int64("userID", userID),
+		slog.Int64("productID", productID),
+	)
+
+	product, err := c.products.Product(ctx, productID)
+	if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+		log.Error("Failed to get product", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	//Для пропавшего товара или варианта остаток не проверяем: Checkout все равно сообщит о проблеме.
+	//Общий предел MaxCartItemQuantity действует всегда
+	maxQuantity := models.MaxCartItemQuantity
+	if product != nil {
+		if limit, err := itemLimit(*product, variantID); err == nil {
+			maxQuantity = limit
+		}
+	}
+
+	if err := c.storage.SetQuantity(ctx, userID, productID, variantID, quantity, maxQuantity); err != nil {
+		if errors.Is(err, models.ErrCartItemNotFound) {
+			log.Warn("Item not in cart")
+			return nil, fmt.Errorf("%s: %w", op, models.ErrCartItemNotFound)
+		}
+		if errors.Is(err, models.ErrCartQuantityExceeded) {
+			log.Warn("Cart quantity exceeded", slog.Int("max", int(maxQuantity)))
+			return nil, fmt.Errorf("%s: %w", op, models.ErrCartQuantityExceeded)
+		}
+		log.Error("Failed to update quantity", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return c.GetCart(ctx, userID)
+}
+
+// itemLimit - сколько штук товара или варианта можно держать в корзине: не больше остатка
+// и не больше MaxCartItemQuantity
+func itemLimit(product models.Product, variantID int64) (int32, error) {
+	stock := product.Stock
+	if variantID != 0 {
+		variant, ok := product.Variant(variantID)
+		if !ok {
+			return 0, models.ErrVariantNotFound
+		}
+		stock = variant.Stock
+	} else if len(product.Variants) > 0 {
+		return 0, models.ErrVariantRequired
+	}
+	return min(stock, models.MaxCartItemQuantity), nil
+}
+
+func (c *Cart) RemoveItem(ctx context.Context, userID, productID, variantID int64) (*models.Cart, error) {
+	const op = "cart.RemoveItem"
+
+	log := c.log.With(
+		slog.String("operation", op),
+		slog.Int64("userID", userID),
+		slog.Int64("productID", productID),
+	)
+
+	if err := c.storage.RemoveItem(ctx, userID, productID, variantID); err != nil {
+		if errors.Is(err, models.ErrCartItemNotFound) {
+			log.Warn("Item not in cart")
+			return nil, fmt.Errorf("%s: %w", op, models.ErrCartItemNotFound)
+		}
+		log.Error("Failed to remove item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return c.GetCart(ctx, userID)
+}
+
+// GetCart возвращает корзину с актуальными названиями, ценами и остатками из каталога
+func (c *Cart) GetCart(ctx context.Context, userID int64) (*models.Cart, error) {
+	const op = "cart.GetCart"
+
+	log := c.log.With(slog.String("operation", op), slog.Int64("userID", userID))
+
+	items, err := c.storage.Items(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get cart", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	cart := &models.Cart{UserID: userID}
+	for _, item := range items {
+		product, err := c.products.Product(ctx, item.ProductID)
+		if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+			log.Error("Failed to get product", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		//Товар могли удалить из каталога - оставляем строку, Checkout сообщит о проблеме
+		if product != nil {
+			item.Name = product.Name
+			item.Price = product.Price
+			item.Stock = product.Stock
+			//У варианта свои цена и остаток; пропавший вариант тоже оставляем для Checkout
+			if item.VariantID != 0 {
+				variant, _ := product.Variant(item.VariantID)
+				item.SKU = variant.SKU
+				item.Price = variant.Price
+				item.Stock = variant.Stock
+			}
+			lineTotal, err := item.Price.Mul(item.Quantity)
+			if err == nil {
+				cart.Total, err = cart.Total.Add(lineTotal)
+			}
+			if err != nil {
+				log.Error("Cart has items in different currencies", slog.String("error", err.Error()))
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	return cart, nil
+}
+
+func (c *Cart) Clear(ctx context.Context, userID int64) error {
+	const op = "cart.Clear"
+
+	if err := c.storage.Clear(ctx, userID); err != nil {
+		c.log.Error("Failed to clear cart",
+			slog.String("operation", op),
+			slog.Int64("userID", userID),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Checkout превращает корзину в заказ. Если какие-то строки нельзя зарезервировать,
+// заказ не создается, корзина остается как есть, а проблемы возвращаются в результате.
+func (c *Cart) Checkout(ctx context.Context, userID int64) (*models.CheckoutResult, error) {
+	const op = "cart.Checkout"
+
+	log := c.log.With(slog.String("operation", op), slog.Int64("userID", userID))
+	log.Info("Starting Checkout")
+
+	items, err := c.storage.Items(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get cart", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrCartEmpty)
+	}
+
+	orderItems := make([]models.OrderItem, 0, len(items))
+	for _, item := range items {
+		orderItems = append(orderItems, models.OrderItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+
+	order, err := c.orders.MakeOrder(ctx, userID, orderItems)
+	if err != nil {
+		var stockErr *models.StockError
+		if errors.As(err, &stockErr) {
+			log.Warn("Checkout has stock problems", slog.Int("problems", len(stockErr.Problems)))
+			return &models.CheckoutResult{Problems: stockErr.Problems}, nil
+		}
+		log.Error("Failed to make order", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	//Заказ уже создан, поэтому ошибку очистки корзины только логируем
+	if err := c.storage.Clear(ctx, userID); err != nil {
+		log.Error("Failed to clear cart after checkout", slog.String("error", err.Error()))
+	}
+
+	log.Info("Checkout done", slog.Int64("orderID", order.ID))
+	return &models.CheckoutResult{Order: order}, nil
+}
@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type KeyStorage interface {
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// Cleaner периодически удаляет ключи идемпотентности старше ttl.
+// Клиент повторяет запрос в пределах минут, поэтому суток хватает с запасом,
+// а таблица не растет бесконечно. ttl должен быть больше аренды ключа в хранилище,
+// иначе удалится ключ запроса, который еще выполняется
+type Cleaner struct {
+	log      *slog.Logger
+	storage  KeyStorage
+	ttl      time.Duration
+	interval time.Duration
+	batch    int
+}
+
+func NewCleaner(log *slog.Logger, storage KeyStorage, ttl, interval time.Duration, batch int) *Cleaner {
+	return &Cleaner{
+		log:      log,
+		storage:  storage,
+		ttl:      ttl,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run работает до отмены контекста
+func (c *Cleaner) Run(ctx context.Context) {
+	const op = "idempotency.Cleaner.Run"
+
+	log := c.log.With(slog.String("operation", op))
+	log.Info("starting idempotency key cleaner", slog.Duration("interval", c.interval), slog.Duration("ttl", c.ttl))
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("idempotency key cleaner stopped")
+			return
+		case <-ticker.C:
+			deleted, err := c.Cleanup(ctx)
+			if err != nil {
+				log.Error("failed to delete expired idempotency keys", slog.String("error", err.Error()))
+				continue
+			}
+			if deleted > 0 {
+				log.Info("expired idempotency keys deleted", slog.Int("count", deleted))
+			}
+		}
+	}
+}
+
+// Cleanup удаляет истекшие ключи пачками, пока они не закончатся, и возвращает количество удаленных
+func (c *Cleaner) Cleanup(ctx context.Context) (int, error) {
+	before := time.Now().Add(-c.ttl)
+	total := 0
+	for {
+		deleted, err := c.storage.DeleteExpiredIdempotencyKeys(ctx, before, c.batch)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < c.batch || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+	"time"
+)
+
+// EventPublisher доставляет событие во внешнюю систему. Ошибка означает, что событие
+// не доставлено и будет отправлено повторно
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+type EventStorage interface {
+	PublishPendingEvents(ctx context.Context, limit int, publish func(ctx context.Context, event models.Event) error) (int, error)
+}
+
+// Relay периодически публикует события из outbox.
+// Доставка at-least-once: событие отмечается опубликованным только после успешного Publish.
+// Порядок внутри заказа сохраняется: если событие заказа не доставлено, следующие события
+// этого заказа в пачке откладываются до следующей попытки.
+// Пачка публикуется внутри транзакции хранилища, поэтому каждая публикация ограничена publishTimeout,
+// а после первого таймаута остаток пачки откладывается: брокер, скорее всего, недоступен
+type Relay struct {
+	log            *slog.Logger
+	storage        EventStorage
+	publisher      EventPublisher
+	interval       time.Duration
+	batch          int
+	publishTimeout time.Duration
+}
+
+func NewRelay(log *slog.Logger, storage EventStorage, publisher EventPublisher, interval time.Duration, batch int, publishTimeout time.Duration) *Relay {
+	return &Relay{
+		log:            log,
+		storage:        storage,
+		publisher:      publisher,
+		interval:       interval,
+		batch:          batch,
+		publishTimeout: publishTimeout,
+	}
+}
+
+// Run работает до отмены контекста
+func (r *Relay) Run(ctx context.Context) {
+	const op = "outbox.Relay.Run"
+
+	log := r.log.With(slog.String("operation", op))
+	log.Info("starting outbox relay", slog.Duration("interval", r.interval))
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+			published, err := r.PublishBatch(ctx)
+			if err != nil {
+				log.Error("failed to publish events", slog.String("error", err.Error()))
+				continue
+			}
+			if published > 0 {
+				log.Debug("events published", slog.Int("count", published))
+			}
+		}
+	}
+}
+
+// PublishBatch публикует одну пачку событий и возвращает количество доставленных
+func (r *Relay) PublishBatch(ctx context.Context) (int, error) {
+	type aggregateKey struct {
+		aggregateType string
+		id            int64
+	}
+	blocked := make(map[aggregateKey]bool)
+	stalled := false
+
+	return r.storage.PublishPendingEvents(ctx, r.batch, func(ctx context.Context, event models.Event) error {
+		key := aggregateKey{event.AggregateType, event.AggregateID}
+		if blocked[key] || stalled {
+			return models.ErrEventDeferred
+		}
+		publishCtx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+		defer cancel()
+		if err := r.publisher.Publish(publishCtx, event); err != nil {
+			blocked[key] = true
+			stalled = publishCtx.Err() != nil
+			r.log.Warn("failed to publish event",
+				slog.Int64("event_id", event.ID),
+				slog.String("type", event.Type),
+				slog.Int64("aggregate_id", event.AggregateID),
+				slog.Int("attempts", int(event.Attempts)+1),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("publish event %d: %w", event.ID, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"github.com/kavshevnova/product-reservation-system/pkg/publishers/memorypublisher"
+)
+
+// memoryOutbox повторяет контракт shopstorage.PublishPendingEvents без базы
+type memoryOutbox struct {
+	events    []models.Event
+	published map[int64]bool
+}
+
+func newMemoryOutbox(events ...models.Event) *memoryOutbox {
+	return &memoryOutbox{events: events, published: make(map[int64]bool)}
+}
+
+func (o *memoryOutbox) PublishPendingEvents(ctx context.Context, limit int, publish func(ctx context.Context, event models.Event) error) (int, error) {
+	published := 0
+	for i := range o.events {
+		event := &o.events[i]
+		if o.published[event.ID] {
+			continue
+		}
+		if limit == 0 {
+			break
+		}
+		limit--
+		err := publish(ctx, *event)
+		switch {
+		case err == nil:
+			o.published[event.ID] = true
+			event.Attempts++
+			published++
+		case errors.Is(err, models.ErrEventDeferred):
+		default:
+			event.Attempts++
+		}
+	}
+	return published, nil
+}
+
+func (o *memoryOutbox) attempts(id int64) int32 {
+	for _, event := range o.events {
+		if event.ID == id {
+			return event.Attempts
+		}
+	}
+	return -1
+}
+
+// flakyPublisher отказывает в публикации выбранных событий, остальные передает в memorypublisher.
+// Событие из hang ждет, пока не истечет контекст, как запрос к зависшему брокеру
+type flakyPublisher struct {
+	*memorypublisher.Publisher
+	mu    sync.Mutex
+	fail  map[int64]bool
+	hang  map[int64]bool
+	calls int
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event models.Event) error {
+	p.mu.Lock()
+	p.calls++
+	fail, hang := p.fail[event.ID], p.hang[event.ID]
+	p.mu.Unlock()
+
+	if hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if fail {
+		return errors.New("broker unavailable")
+	}
+	return p.Publisher.Publish(ctx, event)
+}
+
+func newTestRelay(storage EventStorage, fail, hang map[int64]bool, timeout time.Duration) (*Relay, *flakyPublisher) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	publisher := &flakyPublisher{Publisher: memorypublisher.New(log, 10), fail: fail, hang: hang}
+	return NewRelay(log, storage, publisher, time.Second, 10, timeout), publisher
+}
+
+func orderEvent(id, orderID int64) models.Event {
+	return models.Event{ID: id, AggregateType: "order", AggregateID: orderID, Type: "order.status_changed"}
+}
+
+func publishedIDs(p *flakyPublisher) []int64 {
+	var ids []int64
+	for _, event := range p.Events() {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPublishBatchDefersLaterEventsOfFailedOrder(t *testing.T) {
+	storage := newMemoryOutbox(
+		orderEvent(1, 100),
+		orderEvent(2, 200),
+		orderEvent(3, 100),
+		orderEvent(4, 200),
+		//Тот же aggregate_id, но у товара: неудача заказа 100 его не блокирует
+		models.Event{ID: 5, AggregateType: "product", AggregateID: 100, Type: "product.updated"},
+	)
+	relay, publisher := newTestRelay(storage, map[int64]bool{1: true}, nil, time.Second)
+
+	published, err := relay.PublishBatch(context.Background())
+	if err != nil {
+		t.Fatalf("PublishBatch: %v", err)
+	}
+	if published != 3 {
+		t.Fatalf("published = %d, want 3", published)
+	}
+	if got := publishedIDs(publisher); !equalIDs(got, []int64{2, 4, 5}) {
+		t.Fatalf("published events = %v, want [2 4 5]", got)
+	}
+	//Отложенное событие не тратит попытки, неудачное - тратит
+	if storage.attempts(1) != 1 || storage.attempts(3) != 0 {
+		t.Fatalf("attempts: event 1 = %d, event 3 = %d, want 1 and 0", storage.attempts(1), storage.attempts(3))
+	}
+
+	//Брокер снова принимает событие 1: заказ 100 доходит в исходном порядке
+	publisher.fail = nil
+	published, err = relay.PublishBatch(context.Background())
+	if err != nil {
+		t.Fatalf("second PublishBatch: %v", err)
+	}
+	if published != 2 {
+		t.Fatalf("second batch published = %d, want 2", published)
+	}
+	if got := publishedIDs(publisher); !equalIDs(got, []int64{2, 4, 5, 1, 3}) {
+		t.Fatalf("published events = %v, want [2 4 5 1 3]", got)
+	}
+	if storage.attempts(1) != 2 || storage.attempts(3) != 1 {
+		t.Fatalf("attempts: event 1 = %d, event 3 = %d, want 2 and 1", storage.attempts(1), storage.attempts(3))
+	}
+}
+
+func TestPublishBatchStopsAfterTimeout(t *testing.T) {
+	storage := newMemoryOutbox(orderEvent(1, 100), orderEvent(2, 200), orderEvent(3, 300))
+	relay, publisher := newTestRelay(storage, nil, map[int64]bool{1: true}, 20*time.Millisecond)
+
+	start := time.Now()
+	published, err := relay.PublishBatch(context.Background())
+	if err != nil {
+		t.Fatalf("PublishBatch: %v", err)
+	}
+	//Пачка ждет только одну зависшую публикацию, а не по таймауту на каждое событие
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("PublishBatch took %v", elapsed)
+	}
+	if published != 0 || publisher.calls != 1 {
+		t.Fatalf("published = %d, publisher calls = %d, want 0 and 1", published, publisher.calls)
+	}
+	if storage.attempts(1) != 1 || storage.attempts(2) != 0 || storage.attempts(3) != 0 {
+		t.Fatalf("attempts = %d, %d, %d, want 1, 0, 0", storage.attempts(1), storage.attempts(2), storage.attempts(3))
+	}
+
+	publisher.hang = nil
+	published, err = relay.PublishBatch(context.Background())
+	if err != nil {
+		t.Fatalf("second PublishBatch: %v", err)
+	}
+	if got := publishedIDs(publisher); published != 3 || !equalIDs(got, []int64{1, 2, 3}) {
+		t.Fatalf("published = %d, events = %v, want 3 and [1 2 3]", published, got)
+	}
+}
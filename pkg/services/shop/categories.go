@@ -0,0 +1,86 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+)
+
+// ListCategories возвращает категории каталога списком.
+// parentID ограничивает список прямыми подкатегориями, all - вернуть все категории
+func (s *Shop) ListCategories(ctx context.Context, parentID int64, all bool) ([]models.Category, error) {
+	const op = "shop.ListCategories"
+
+	log := s.log.With(slog.String("operation", op), slog.Int64("parent_id", parentID))
+	log.Info("Starting List Categories")
+
+	categories, err := s.storage.Categories(ctx)
+	if err != nil {
+		log.Error("ListCategories failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if all {
+		return categories, nil
+	}
+
+	if parentID != 0 && !containsCategory(categories, parentID) {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrCategoryNotFound)
+	}
+	var children []models.Category
+	for _, category := range categories {
+		if category.ParentID == parentID {
+			children = append(children, category)
+		}
+	}
+	log.Info("List Categories done", slog.Int("count", len(children)))
+	return children, nil
+}
+
+// GetCategoryTree возвращает дерево категорий. rootID = 0 - весь каталог
+func (s *Shop) GetCategoryTree(ctx context.Context, rootID int64) ([]*models.CategoryNode, error) {
+	const op = "shop.GetCategoryTree"
+
+	log := s.log.With(slog.String("operation", op), slog.Int64("root_id", rootID))
+	log.Info("Starting Get Category Tree")
+
+	categories, err := s.storage.Categories(ctx)
+	if err != nil {
+		log.Error("GetCategoryTree failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	//Категорий немного, поэтому дерево собираем в памяти из плоского списка
+	nodes := make(map[int64]*models.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &models.CategoryNode{Category: category}
+	}
+	var roots []*models.CategoryNode
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if parent, ok := nodes[category.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	if rootID != 0 {
+		root, ok := nodes[rootID]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", op, models.ErrCategoryNotFound)
+		}
+		roots = []*models.CategoryNode{root}
+	}
+	log.Info("Get Category Tree done")
+	return roots, nil
+}
+
+func containsCategory(categories []models.Category, id int64) bool {
+	for _, category := range categories {
+		if category.ID == id {
+			return true
+		}
+	}
+	return false
+}
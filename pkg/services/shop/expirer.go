@@ -0,0 +1,99 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+	"time"
+)
+
+type ExpiredReservationsProvider interface {
+	ExpiredReservations(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+type ReservationCanceler interface {
+	ExpireReservation(ctx context.Context, orderID int64) error
+}
+
+// ReservationExpirer периодически отменяет неоплаченные резервации и возвращает товар на склад.
+// Можно запускать на нескольких репликах одновременно: CancelReservation блокирует заказ
+// и применяет переход, только если статус все еще reserved, поэтому заказ, уже отмененный
+// другой репликой или успевший оплатиться, просто пропускается.
+type ReservationExpirer struct {
+	log      *slog.Logger
+	orders   ExpiredReservationsProvider
+	canceler ReservationCanceler
+	interval time.Duration
+	batch    int
+}
+
+func NewReservationExpirer(
+	log *slog.Logger,
+	orders ExpiredReservationsProvider,
+	canceler ReservationCanceler,
+	interval time.Duration,
+	batch int,
+) *ReservationExpirer {
+	return &ReservationExpirer{
+		log:      log,
+		orders:   orders,
+		canceler: canceler,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run работает до отмены контекста
+func (e *ReservationExpirer) Run(ctx context.Context) {
+	const op = "shop.ReservationExpirer.Run"
+
+	log := e.log.With(slog.String("operation", op))
+	log.Info("starting reservation expirer", slog.Duration("interval", e.interval))
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("reservation expirer stopped")
+			return
+		case <-ticker.C:
+			expired, err := e.ExpireBatch(ctx)
+			if err != nil {
+				log.Error("failed to expire reservations", slog.String("error", err.Error()))
+				continue
+			}
+			if expired > 0 {
+				log.Info("reservations expired", slog.Int("count", expired))
+			}
+		}
+	}
+}
+
+// ExpireBatch отменяет одну пачку истекших резерваций и возвращает количество отмененных
+func (e *ReservationExpirer) ExpireBatch(ctx context.Context) (int, error) {
+	ids, err := e.orders.ExpiredReservations(ctx, time.Now(), e.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, orderID := range ids {
+		err := e.canceler.ExpireReservation(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidTransition) {
+				//Отменен другой репликой или оплачен
+				continue
+			}
+			e.log.Error("failed to cancel expired reservation",
+				slog.Int64("order_id", orderID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
@@ -0,0 +1,158 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/authctx"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+)
+
+// transitionOrder - единственное место, где меняется статус заказа.
+// Переход проверяется по models.OrderStatus.CanTransitionTo, а хранилище применяет его,
+// только если статус не успел измениться, и записывает его в историю.
+func (s *Shop) transitionOrder(ctx context.Context, orderID int64, to models.OrderStatus, actor string) (*models.Order, error) {
+	return s.transitionOrderWithReason(ctx, orderID, to, actor, "")
+}
+
+func (s *Shop) transitionOrderWithReason(ctx context.Context, orderID int64, to models.OrderStatus, actor, reason string) (*models.Order, error) {
+	const op = "shop.transitionOrder"
+
+	log := s.log.With(
+		slog.String("operation", op),
+		slog.Int64("order_id", orderID),
+		slog.String("to", string(to)),
+		slog.String("actor", actor),
+	)
+
+	order, err := s.storage.Order(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !order.Status.CanTransitionTo(to) {
+		log.Warn("invalid transition", slog.String("from", string(order.Status)))
+		return nil, fmt.Errorf("%s: %s -> %s: %w", op, order.Status, to, models.ErrInvalidTransition)
+	}
+
+	t := models.OrderTransition{
+		OrderID: orderID,
+		From:    order.Status,
+		To:      to,
+		Actor:   actor,
+		Reason:  reason,
+	}
+	switch to {
+	case models.OrderStatusPaid:
+		order, err = s.inventory.ConfirmOrder(ctx, t)
+	case models.OrderStatusCanceled, models.OrderStatusExpired:
+		//Отмена и истечение возвращают товар на склад, за оплаченный заказ создается заявка на возврат
+		var refund *models.Refund
+		refund, err = s.inventory.CancelReservation(ctx, t)
+		if refund != nil {
+			order.Refunds = append(order.Refunds, *refund)
+		}
+		order.Status = to
+	default:
+		err = s.inventory.UpdateOrderStatus(ctx, t)
+		order.Status = to
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("order status changed", slog.String("from", string(t.From)))
+	return order, nil
+}
+
+// UpdateOrderStatus - ручная смена статуса сотрудником: отгрузка и доставка.
+// Оплата, отмена и возвраты идут через свои методы.
+func (s *Shop) UpdateOrderStatus(ctx context.Context, orderID int64, status models.OrderStatus) (*models.Order, error) {
+	const op = "shop.UpdateOrderStatus"
+
+	if status != models.OrderStatusShipped && status != models.OrderStatusDelivered {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidStatus)
+	}
+
+	order, err := s.transitionOrder(ctx, orderID, status, actorFromContext(ctx, models.ActorSystem))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return order, nil
+}
+
+// CancelOrder - отмена заказа покупателем. Отменить можно резервацию или оплаченный,
+// но еще не отгруженный заказ; в последнем случае создается заявка на возврат денег
+func (s *Shop) CancelOrder(ctx context.Context, userID, orderID int64, reason string) (*models.Order, error) {
+	const op = "shop.CancelOrder"
+
+	log := s.log.With(
+		slog.String("operation", op),
+		slog.Int64("user_id", userID),
+		slog.Int64("order_id", orderID),
+	)
+	log.Info("Starting Cancel Order")
+
+	order, err := s.storage.Order(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	//Чужой заказ для пользователя не существует
+	if order.UserID != userID {
+		log.Warn("order belongs to another user")
+		return nil, fmt.Errorf("%s: %w", op, models.ErrOrderNotFound)
+	}
+	if order.Status != models.OrderStatusReserved && order.Status != models.OrderStatusPaid {
+		return nil, fmt.Errorf("%s: %s: %w", op, order.Status, models.ErrOrderNotCancelable)
+	}
+
+	order, err = s.transitionOrderWithReason(ctx, orderID, models.OrderStatusCanceled, models.UserActor(userID), reason)
+	if err != nil {
+		//Статус успел измениться, например заказ отгрузили
+		if errors.Is(err, models.ErrInvalidTransition) {
+			return nil, fmt.Errorf("%s: %w", op, models.ErrOrderNotCancelable)
+		}
+		log.Error("CancelOrder failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	//За оплаченный заказ сразу возвращаем деньги. Если провайдер недоступен, отмена все равно состоялась:
+	//возврат остается requested, и его проводит RefundOrder
+	if order.PendingRefundAmount().Amount > 0 {
+		result, err := s.RefundOrder(ctx, orderID, nil, 0, reason)
+		if err != nil {
+			log.Error("Failed to pay out cancel refund", slog.String("error", err.Error()))
+		} else {
+			for i := range order.Refunds {
+				if order.Refunds[i].ID == result.Refund.ID {
+					order.Refunds[i] = result.Refund
+				}
+			}
+		}
+	}
+
+	log.Info("Cancel Order done", slog.Bool("refund_requested", len(order.Refunds) > 0))
+	return order, nil
+}
+
+// ExpireReservation отменяет неоплаченную резервацию по истечении срока
+func (s *Shop) ExpireReservation(ctx context.Context, orderID int64) error {
+	const op = "shop.ExpireReservation"
+
+	_, err := s.transitionOrder(ctx, orderID, models.OrderStatusExpired, models.ActorExpirer)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidTransition) {
+			return fmt.Errorf("%s: %w", op, models.ErrInvalidTransition)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// actorFromContext - инициатор для истории статусов: пользователь из токена или fallback
+func actorFromContext(ctx context.Context, fallback string) string {
+	if principal, ok := authctx.PrincipalFromContext(ctx); ok {
+		return models.UserActor(principal.UserID)
+	}
+	return fallback
+}
@@ -0,0 +1,72 @@
+package shop
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// Токен страницы непрозрачен для клиента: это base64 от JSON с курсором
+func encodePageToken(cursor any) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return models.ErrInvalidPageToken
+	}
+	return nil
+}
+
+// pageSize приводит размер страницы из запроса к допустимому
+func pageSize(limit int32) int32 {
+	if limit <= 0 {
+		return models.DefaultPageSize
+	}
+	if limit > models.MaxPageSize {
+		return models.MaxPageSize
+	}
+	return limit
+}
+
+// productCursor запоминает последний товар страницы вместе со значением колонки сортировки
+func productCursor(sort models.ProductSort, last models.Product) models.ProductCursor {
+	cursor := models.ProductCursor{Sort: sort, ID: last.ProductID}
+	switch sort {
+	case models.ProductSortPriceAsc, models.ProductSortPriceDesc:
+		cursor.Value = last.Price.Decimal()
+	case models.ProductSortNameAsc, models.ProductSortNameDesc:
+		cursor.Value = last.Name
+	}
+	return cursor
+}
+
+// validProductCursor проверяет курсор из токена до того, как он попадет в SQL.
+// Токен приходит от клиента, и подделанное значение должно давать ErrInvalidPageToken, а не ошибку базы
+func validProductCursor(cursor models.ProductCursor) bool {
+	if cursor.ID < 0 {
+		return false
+	}
+	switch cursor.Sort {
+	case models.ProductSortPriceAsc, models.ProductSortPriceDesc:
+		//Цену сравниваем как ::numeric, поэтому значение должно быть суммой в записи Money.Decimal
+		price, err := models.ParseMoney(cursor.Value, "")
+		return err == nil && price.Decimal() == cursor.Value
+	case models.ProductSortNameAsc, models.ProductSortNameDesc:
+		//Postgres не принимает в тексте нулевой байт и невалидный UTF-8
+		return utf8.ValidString(cursor.Value) && !strings.ContainsRune(cursor.Value, 0)
+	case models.ProductSortDefault, models.ProductSortNewest:
+		return cursor.Value == ""
+	}
+	return true
+}
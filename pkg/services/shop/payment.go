@@ -0,0 +1,51 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+// PaymentProvider - платежный провайдер (PSP). Магазин не знает, как устроена оплата:
+// он открывает сессию на сумму заказа, спрашивает ее статус и возвращает деньги.
+type PaymentProvider interface {
+	// CreatePayment открывает сессию оплаты заказа. Повторный вызов для того же заказа
+	// возвращает уже открытую сессию, а не создает вторую
+	CreatePayment(ctx context.Context, order models.Order) (*models.PaymentSession, error)
+	PaymentStatus(ctx context.Context, externalID string) (models.PaymentStatus, error)
+	Refund(ctx context.Context, externalID string, amount models.Money) (*models.ProviderRefund, error)
+}
+
+// PaymentStorage - история платежей заказа: открытые сессии и попытки оплаты по ним
+type PaymentStorage interface {
+	SavePayment(ctx context.Context, session models.PaymentSession) (*models.Payment, error)
+	RecordPaymentAttempt(ctx context.Context, result models.PaymentResult) (*models.Payment, error)
+	CreateRefund(ctx context.Context, refund models.Refund) (*models.Refund, error)
+	ClaimRefund(ctx context.Context, orderID int64) (*models.Refund, error)
+	CompleteRefund(ctx context.Context, refund models.Refund) error
+}
+
+// openPayment открывает у провайдера сессию оплаты заказа и записывает ее в историю платежей
+func (s *Shop) openPayment(ctx context.Context, order models.Order) (*models.Payment, error) {
+	const op = "shop.openPayment"
+
+	session, err := s.payments.CreatePayment(ctx, order)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, models.ErrPaymentUnavailable, err)
+	}
+	payment, err := s.paymentStore.SavePayment(ctx, *session)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return payment, nil
+}
+
+// pendingPayment - последний платеж заказа, который еще ждет оплаты
+func pendingPayment(order models.Order) (models.Payment, bool) {
+	for i := len(order.Payments) - 1; i >= 0; i-- {
+		if order.Payments[i].Status == models.PaymentStatusPending {
+			return order.Payments[i], true
+		}
+	}
+	return models.Payment{}, false
+}
@@ -0,0 +1,229 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+	"log/slog"
+)
+
+// RequestReturn создает заявку покупателя на возврат части оплаченного заказа.
+// Чужой заказ для покупателя не существует
+func (s *Shop) RequestReturn(ctx context.Context, userID, orderID int64, items []models.ReturnItem, reason string) (*models.Return, error) {
+	const op = "shop.RequestReturn"
+
+	log := s.log.With(
+		slog.String("operation", op),
+		slog.Int64("userID", userID),
+		slog.Int64("order_id", orderID),
+	)
+	log.Info("Starting Request Return")
+
+	order, err := s.storage.Order(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, models.ErrOrderNotFound) {
+			log.Warn("Order not found")
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if order.UserID != userID {
+		log.Warn("Order belongs to another user")
+		return nil, fmt.Errorf("%s: %w", op, models.ErrOrderNotFound)
+	}
+
+	ret, err := s.inventory.CreateReturn(ctx, models.Return{OrderID: orderID, Reason: reason, Items: items})
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrOrderNotReturnable), errors.Is(err, models.ErrReturnExceedsOrder):
+			log.Warn("Return rejected", slog.String("error", err.Error()))
+		default:
+			log.Error("CreateReturn failed", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Return requested", slog.Int64("return_id", ret.ID), slog.String("amount", ret.Amount.String()))
+	return ret, nil
+}
+
+// ApproveReturn - решение сотрудника по заявке. restock возвращает товар на склад,
+// если он пригоден к продаже. Деньги возвращаются отдельно через RefundOrder
+func (s *Shop) ApproveReturn(ctx context.Context, returnID int64, restock bool) (*models.Return, error) {
+	const op = "shop.ApproveReturn"
+
+	log := s.log.With(
+		slog.String("operation", op),
+		slog.Int64("return_id", returnID),
+		slog.Bool("restock", restock),
+	)
+	log.Info("Starting Approve Return")
+
+	ret, err := s.inventory.ApproveReturn(ctx, returnID, restock)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReturnNotFound), errors.Is(err, models.ErrReturnNotApprovable):
+			log.Warn("Cannot approve return", slog.String("error", err.Error()))
+		default:
+			log.Error("ApproveReturn failed", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Return approved", slog.Int64("order_id", ret.OrderID))
+	return ret, nil
+}
+
+// RefundOrder возвращает деньги за заказ через платежного провайдера.
+// amount == nil означает сумму одобренной заявки returnID, а без заявки - сначала ждущий выплаты
+// requested-возврат (например, после отмены оплаченного заказа), иначе весь невозвращенный остаток.
+// Явная amount вместе с returnID не может превышать сумму заявки.
+// Когда возвращена вся сумма, заказ переходит в refunded
+func (s *Shop) RefundOrder(ctx context.Context, orderID int64, amount *models.Money, returnID int64, reason string) (*models.RefundResult, error) {
+	const op = "shop.RefundOrder"
+
+	log := s.log.With(
+		slog.String("operation", op),
+		slog.Int64("order_id", orderID),
+		slog.Int64("return_id", returnID),
+	)
+	log.Info("Starting Refund Order")
+
+	order, err := s.storage.OrderDetails(ctx, orderID)
+	if err != nil {
+		if !errors.Is(err, models.ErrOrderNotFound) {
+			log.Error("OrderDetails failed", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payment, ok := succeededPayment(*order)
+	if !ok {
+		log.Warn("Order has no succeeded payment")
+		return nil, fmt.Errorf("%s: %w", op, models.ErrPaymentNotFound)
+	}
+
+	//Ждущий выплаты возврат уже зарезервировал сумму, его нужно только провести через провайдера
+	if amount == nil && returnID == 0 {
+		claimed, err := s.paymentStore.ClaimRefund(ctx, orderID)
+		switch {
+		case err == nil:
+			log.Info("Settling requested refund", slog.Int64("refund_id", claimed.ID))
+			return s.payoutRefund(ctx, log, order, payment, *claimed, true)
+		case !errors.Is(err, models.ErrRefundNotFound):
+			log.Error("ClaimRefund failed", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	refund := models.Refund{OrderID: orderID, ReturnID: returnID, Reason: reason}
+	switch {
+	case returnID != 0:
+		ret, ok := orderReturn(*order, returnID)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", op, models.ErrReturnNotFound)
+		}
+		refund.Amount = ret.Amount
+		//Явная сумма по заявке допустима только в пределах ее стоимости
+		if amount != nil {
+			if amount.Amount > ret.Amount.Amount {
+				log.Warn("Refund exceeds return amount", slog.String("amount", amount.String()), slog.String("return_amount", ret.Amount.String()))
+				return nil, fmt.Errorf("%s: %w", op, models.ErrRefundExceedsReturn)
+			}
+			refund.Amount = *amount
+		}
+	case amount != nil:
+		refund.Amount = *amount
+	default:
+		refund.Amount, err = order.Sum.Sub(order.RefundedAmount())
+		if err == nil {
+			refund.Amount, err = refund.Amount.Sub(order.PendingRefundAmount())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if refund.Amount.Currency == "" {
+		refund.Amount.Currency = order.Sum.Currency
+	}
+	if refund.Amount.Amount <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrNothingToRefund)
+	}
+
+	//Сначала резервируем сумму у себя, чтобы параллельные возвраты не превысили оплату
+	created, err := s.paymentStore.CreateRefund(ctx, refund)
+	if err != nil {
+		log.Warn("Cannot create refund", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order.Refunds = append(order.Refunds, *created)
+	return s.payoutRefund(ctx, log, order, payment, *created, false)
+}
+
+// payoutRefund проводит processing-возврат через провайдера и записывает результат.
+// Неудачная выплата ждущего возврата (settling) возвращает его в requested, чтобы ее можно было повторить,
+// а новый возврат помечается failed и освобождает зарезервированную сумму
+func (s *Shop) payoutRefund(ctx context.Context, log *slog.Logger, order *models.Order, payment models.Payment, refund models.Refund, settling bool) (*models.RefundResult, error) {
+	const op = "shop.payoutRefund"
+
+	log = log.With(slog.Int64("refund_id", refund.ID))
+
+	providerRefund, err := s.payments.Refund(ctx, payment.ExternalID, refund.Amount)
+	if err != nil {
+		log.Error("Provider refund failed", slog.String("error", err.Error()))
+		refund.Status = models.RefundStatusFailed
+		if settling {
+			refund.Status = models.RefundStatusRequested
+		}
+		if completeErr := s.paymentStore.CompleteRefund(ctx, refund); completeErr != nil {
+			log.Error("Failed to save failed refund", slog.String("error", completeErr.Error()))
+		}
+		if errors.Is(err, models.ErrRefundExceedsAmount) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, fmt.Errorf("%s: %w: %w", op, models.ErrPaymentUnavailable, err)
+	}
+	refund.Status = providerRefund.Status
+	refund.ExternalID = providerRefund.ExternalID
+	if err := s.paymentStore.CompleteRefund(ctx, refund); err != nil {
+		//Деньги уже вернулись у провайдера, запись останется processing до ручного разбора
+		log.Error("Failed to save refund result", slog.String("error", err.Error()), slog.String("external_id", refund.ExternalID))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Refund issued", slog.String("amount", refund.Amount.String()), slog.String("external_id", refund.ExternalID))
+
+	for i := range order.Refunds {
+		if order.Refunds[i].ID == refund.ID {
+			order.Refunds[i] = refund
+		}
+	}
+	refunded := order.RefundedAmount()
+	if refunded.Amount >= order.Sum.Amount && order.Status.CanTransitionTo(models.OrderStatusRefunded) {
+		updated, err := s.transitionOrderWithReason(ctx, order.ID, models.OrderStatusRefunded, actorFromContext(ctx, models.ActorSystem), refund.Reason)
+		if err != nil {
+			log.Error("Failed to mark order refunded", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		order.Status = updated.Status
+	}
+
+	return &models.RefundResult{Refund: refund, Order: *order}, nil
+}
+
+// succeededPayment - последний оплаченный платеж заказа: через него идут возвраты
+func succeededPayment(order models.Order) (models.Payment, bool) {
+	for i := len(order.Payments) - 1; i >= 0; i-- {
+		switch order.Payments[i].Status {
+		case models.PaymentStatusSucceeded, models.PaymentStatusRefunded:
+			return order.Payments[i], true
+		}
+	}
+	return models.Payment{}, false
+}
+
+func orderReturn(order models.Order, returnID int64) (models.Return, bool) {
+	for _, ret := range order.Returns {
+		if ret.ID == returnID {
+			return ret, true
+		}
+	}
+	return models.Return{}, false
+}
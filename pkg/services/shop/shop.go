@@ -7,53 +7,152 @@ import (
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
 	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Shop struct {
-	log       *slog.Logger
-	storage   ProductStorage
-	inventory InventoryManager
+	log            *slog.Logger
+	storage        ProductStorage
+	inventory      InventoryManager
+	writer         ProductWriter
+	payments       PaymentProvider
+	paymentStore   PaymentStorage
+	reservationTTL time.Duration
 }
 
 type ProductStorage interface {
-	ListProducts(ctx context.Context, limit, offset int32) ([]models.Product, error)
+	ListProducts(ctx context.Context, query models.ProductQuery) ([]models.Product, error)
+	CountProducts(ctx context.Context, query models.ProductQuery) (int64, error)
+	SearchProducts(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error)
+	SearchProductsFuzzy(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error)
+	Categories(ctx context.Context) ([]models.Category, error)
 	Product(ctx context.Context, productID int64) (*models.Product, error)
-	GetOrderHistory(ctx context.Context, userID int64) ([]models.Order, error)
+	GetOrderHistory(ctx context.Context, filter models.OrderHistoryFilter) ([]models.Order, error)
+	Order(ctx context.Context, orderID int64) (*models.Order, error)
+	OrderDetails(ctx context.Context, orderID int64) (*models.Order, error)
+}
+
+type ProductWriter interface {
+	CreateProduct(ctx context.Context, product models.Product) (*models.Product, error)
+	UpdateProduct(ctx context.Context, productID int64, update models.ProductUpdate) (*models.Product, error)
+	DeleteProduct(ctx context.Context, productID int64) error
 }
 
 type InventoryManager interface {
-	ReserveProduct(ctx context.Context, userID, productID int64, quantity int32) (*models.Order, error)
-	CancelReservation(ctx context.Context, orderID int64) error
-	ConfirmOrder(ctx context.Context, orderID int64) (*models.Order, error)
+	ReserveProduct(ctx context.Context, userID int64, items []models.OrderItem, expiresAt time.Time) (*models.Order, error)
+	CancelReservation(ctx context.Context, t models.OrderTransition) (*models.Refund, error)
+	ConfirmOrder(ctx context.Context, t models.OrderTransition) (*models.Order, error)
+	UpdateOrderStatus(ctx context.Context, t models.OrderTransition) error
+	CreateReturn(ctx context.Context, ret models.Return) (*models.Return, error)
+	ApproveReturn(ctx context.Context, returnID int64, restock bool) (*models.Return, error)
 }
 
-func New(log *slog.Logger, storage ProductStorage, inventory InventoryManager) *Shop {
+func New(log *slog.Logger, storage ProductStorage, inventory InventoryManager, writer ProductWriter, payments PaymentProvider, paymentStore PaymentStorage, reservationTTL time.Duration) *Shop {
 	return &Shop{
-		log:       log,
-		storage:   storage,
-		inventory: inventory,
+		log:            log,
+		storage:        storage,
+		inventory:      inventory,
+		writer:         writer,
+		payments:       payments,
+		paymentStore:   paymentStore,
+		reservationTTL: reservationTTL,
 	}
 }
 
-func (s *Shop) ListProducts(ctx context.Context, limit, offset int32) ([]models.Product, error) {
+// ListProducts возвращает страницу каталога. pageToken - курсор из предыдущей страницы
+func (s *Shop) ListProducts(ctx context.Context, query models.ProductQuery, pageToken string) (*models.ProductPage, error) {
 	const op = "shop.ListProducts"
 
 	log := s.log.With(
 		slog.String("operation", op),
-		slog.String("limit", strconv.Itoa(int(limit))),
-		slog.String("offset", strconv.Itoa(int(offset))),
+		slog.String("limit", strconv.Itoa(int(query.Limit))),
+		slog.String("offset", strconv.Itoa(int(query.Offset))),
 	)
 
 	log.Info("Starting list Products")
 
-	products, err := s.storage.ListProducts(ctx, limit, offset)
+	if pageToken != "" {
+		var cursor models.ProductCursor
+		if err := decodePageToken(pageToken, &cursor); err != nil {
+			log.Warn("Invalid page token")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		//Курсор хранит значение колонки сортировки, с другой сортировкой он бессмыслен
+		if cursor.Sort != query.Sort {
+			log.Warn("Page token from another sort")
+			return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidPageToken)
+		}
+		if !validProductCursor(cursor) {
+			log.Warn("Page token with invalid cursor value")
+			return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidPageToken)
+		}
+		query.After = &cursor
+		query.Offset = 0
+	}
+
+	//Берем на один товар больше, чтобы понять, есть ли следующая страница
+	limit := pageSize(query.Limit)
+	query.Limit = limit + 1
+
+	products, err := s.storage.ListProducts(ctx, query)
 	if err != nil {
 		log.Error("ListProducts failed", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	page := &models.ProductPage{Products: products}
+	if int32(len(products)) > limit {
+		page.Products = products[:limit]
+		last := page.Products[limit-1]
+		page.NextPageToken, err = encodePageToken(productCursor(query.Sort, last))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	//Подсчет дорогой на большом каталоге, поэтому только по запросу
+	if query.WithTotal {
+		page.TotalCount, err = s.storage.CountProducts(ctx, query)
+		if err != nil {
+			log.Error("CountProducts failed", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	log.Info("ListProducts done")
-	return products, nil
+	return page, nil
+}
+
+// SearchProducts ищет товары по словам запроса, а если ничего не нашлось - по похожести названия
+func (s *Shop) SearchProducts(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error) {
+	const op = "shop.SearchProducts"
+
+	log := s.log.With(slog.String("operation", op), slog.String("query", query))
+	log.Info("Starting Search Products")
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrEmptySearch)
+	}
+	limit = pageSize(limit)
+
+	hits, err := s.storage.SearchProducts(ctx, query, limit)
+	if err != nil {
+		log.Error("SearchProducts failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(hits) == 0 {
+		//Слова не нашлись, возможно, в запросе опечатка
+		hits, err = s.storage.SearchProductsFuzzy(ctx, query, limit)
+		if err != nil {
+			log.Error("SearchProductsFuzzy failed", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("Search Products done", slog.Int("count", len(hits)))
+	return hits, nil
 }
 
 func (s *Shop) GetProductInfo(ctx context.Context, productID int64) (*models.Product, error) {
@@ -69,7 +168,7 @@ func (s *Shop) GetProductInfo(ctx context.Context, productID int64) (*models.Pro
 	product, err := s.storage.Product(ctx, productID)
 	if err != nil {
 		if errors.Is(err, models.ErrProductNotFound) {
-			s.log.Warn("Product not found", slog.StringValue(err.Error()))
+			s.log.Warn("Product not found", slog.String("error", err.Error()))
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		log.Error("GetProduct failed", slog.String("error", err.Error()))
@@ -79,82 +178,145 @@ func (s *Shop) GetProductInfo(ctx context.Context, productID int64) (*models.Pro
 	return product, nil
 }
 
-func (s *Shop) GetOrdersHistory(ctx context.Context, userID int64) ([]models.Order, error) {
+// GetOrdersHistory возвращает страницу истории заказов. pageToken - курсор из предыдущей страницы
+func (s *Shop) GetOrdersHistory(ctx context.Context, filter models.OrderHistoryFilter, pageToken string) (*models.OrderPage, error) {
 	const op = "shop.OrderHistory"
 
-	log := s.log.With(slog.String("operation", op), slog.String("userID", strconv.Itoa(int(userID))))
+	log := s.log.With(slog.String("operation", op), slog.String("userID", strconv.Itoa(int(filter.UserID))))
 	log.Info("Starting Get OrderHistory")
 
-	orders, err := s.storage.GetOrderHistory(ctx, userID)
+	if pageToken != "" {
+		var cursor models.OrderCursor
+		if err := decodePageToken(pageToken, &cursor); err != nil {
+			log.Warn("Invalid page token")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		filter.After = &cursor
+	}
+
+	//Берем на один заказ больше, чтобы понять, есть ли следующая страница
+	limit := pageSize(filter.Limit)
+	filter.Limit = limit + 1
+
+	orders, err := s.storage.GetOrderHistory(ctx, filter)
 	if err != nil {
 		log.Error("GetOrderHistory failed", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info(("Get OrderHistory done"), slog.Int("count", len(orders)))
-	return orders, nil
+
+	page := &models.OrderPage{Orders: orders}
+	if int32(len(orders)) > limit {
+		page.Orders = orders[:limit]
+		last := page.Orders[limit-1]
+		page.NextPageToken, err = encodePageToken(models.OrderCursor{Time: last.Time, ID: last.ID})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	log.Info(("Get OrderHistory done"), slog.Int("count", len(page.Orders)))
+	return page, nil
 }
 
-func (s *Shop) MakeOrder(ctx context.Context, userID, productID int64, quantity int32) (*models.Order, error) {
+// GetOrder возвращает заказ со строками, историей статусов и данными об оплате.
+// Заказ видят владелец и сотрудники, для остальных он не существует
+func (s *Shop) GetOrder(ctx context.Context, viewer models.Principal, orderID int64) (*models.Order, error) {
+	const op = "shop.GetOrder"
+
+	log := s.log.With(slog.String("operation", op), slog.Int64("order_id", orderID))
+	log.Info("Starting Get Order")
+
+	order, err := s.storage.OrderDetails(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, models.ErrOrderNotFound) {
+			log.Warn("Order not found")
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("GetOrder failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if order.UserID != viewer.UserID && !viewer.IsStaff() {
+		log.Warn("Order belongs to another user", slog.Int64("viewer", viewer.UserID))
+		return nil, fmt.Errorf("%s: %w", op, models.ErrOrderNotFound)
+	}
+
+	//Ссылка на оплату нужна, только пока заказ ждет оплаты
+	if order.Status == models.OrderStatusReserved {
+		if payment, ok := pendingPayment(*order); ok {
+			order.PaymentURL = payment.URL
+		}
+	}
+	log.Info("Get Order done")
+	return order, nil
+}
+
+func (s *Shop) MakeOrder(ctx context.Context, userID int64, items []models.OrderItem) (*models.Order, error) {
 	const op = "shop.MakeOrder"
 
 	log := s.log.With(
 		slog.String("operation", op),
 		slog.String("userID", strconv.Itoa(int(userID))),
-		slog.String("productID", strconv.Itoa(int(productID))),
-		slog.String("quantity", strconv.Itoa(int(quantity))),
+		slog.Int("lines", len(items)),
 	)
 	log.Info("Starting Buy Product")
 
-	//Проверяем наличие товара
+	//Резервируем товары. Наличие проверяется в той же транзакции, что и резервация
 
-	product, err := s.storage.Product(ctx, productID)
+	order, err := s.inventory.ReserveProduct(ctx, userID, items, time.Now().Add(s.reservationTTL))
 	if err != nil {
-		if errors.Is(err, models.ErrProductNotFound) {
-			log.Error("Product not found", slog.String("error", err.Error()))
-			return nil, fmt.Errorf("%s: %w", op, models.ErrProductNotFound)
+		//StockError со списком строк передаем наверх как есть
+		var stockErr *models.StockError
+		if errors.As(err, &stockErr) {
+			log.Warn("Cannot reserve order lines", slog.Int("problems", len(stockErr.Problems)))
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		log.Error("Failed to get product info", slog.String("error", err.Error()))
+		log.Error("Failed to reserve product", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if product.Stock < quantity {
-		log.Error("Not enough stock", slog.String("Available", strconv.Itoa(int(product.Stock))))
-		return nil, fmt.Errorf("%s: %w", op, models.ErrNotEnoughStock)
-	}
-
-	log.Info("Product in stock")
+	log.Info("Reserve Product done", slog.String("orderID", strconv.Itoa(int(order.ID))))
 
-	//Резервируем товар
-
-	order, err := s.inventory.ReserveProduct(ctx, userID, productID, quantity)
+	payment, err := s.openPayment(ctx, *order)
 	if err != nil {
-		log.Error("Failed to reserve product", slog.String("error", err.Error()))
+		//Без сессии заказ оплатить нельзя - сразу возвращаем товар на склад
+		log.Error("Failed to open payment", slog.String("error", err.Error()))
+		if _, cancelErr := s.transitionOrderWithReason(ctx, order.ID, models.OrderStatusCanceled, models.ActorSystem, "payment session failed"); cancelErr != nil {
+			log.Error("Failed to cancel reservation", slog.String("error", cancelErr.Error()))
+		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info("Reserve Product done", slog.String("productID", strconv.Itoa(int(order.ID))))
 
-	//Возвращаем заказ в статусе "ожидает оплаты"
-	return &models.Order{
-		ID:         order.ID,
-		Status:     "waiting_payment",
-		PaymentURL: s.generatePaymentURL(order.ID),
-	}, nil
+	//Возвращаем заказ в статусе reserved: он ждет оплаты до order.ExpiresAt
+	order.PaymentURL = payment.URL
+	order.Payments = []models.Payment{*payment}
+	log.Info("Payment session created", slog.String("payment_id", payment.ExternalID))
+	return order, nil
 }
 
-func (s *Shop) generatePaymentURL(orderID int64) string {
-	return fmt.Sprintf("https://pay.example.com?order_id=%d", orderID)
-}
-
-func (s *Shop) ConfirmPayment(ctx context.Context, orderID int64, success bool) error {
+// ConfirmPayment применяет итог попытки оплаты: сначала записывает его в историю платежей,
+// затем переводит заказ. Попытка сохраняется, даже если заказ уже нельзя перевести,
+// чтобы поддержка видела списание по истекшему или отмененному заказу
+func (s *Shop) ConfirmPayment(ctx context.Context, result models.PaymentResult) error {
 	const op = "services.shop.ConfirmPayment"
+	orderID := result.OrderID
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("order_id", orderID),
-		slog.Bool("success", success),
+		slog.Bool("success", result.Success),
 	)
 
-	if success {
+	actor := actorFromContext(ctx, models.ActorPayment)
+
+	if _, err := s.paymentStore.RecordPaymentAttempt(ctx, result); err != nil {
+		//Ручное подтверждение заказа без записанного платежа (созданного до истории платежей) допускаем
+		if !errors.Is(err, models.ErrPaymentNotFound) || result.ExternalID != "" {
+			log.Error("failed to record payment attempt", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Warn("order has no payment record")
+	}
+
+	if result.Success {
 		// Подтверждаем заказ
-		_, err := s.inventory.ConfirmOrder(ctx, orderID)
+		_, err := s.transitionOrder(ctx, orderID, models.OrderStatusPaid, actor)
 		if err != nil {
 			log.Error("failed to confirm order", slog.String("error", err.Error()))
 			return fmt.Errorf("%s: %w", op, err)
@@ -162,7 +324,7 @@ func (s *Shop) ConfirmPayment(ctx context.Context, orderID int64, success bool)
 		log.Info("payment confirmed")
 	} else {
 		// Отменяем резервацию
-		err := s.inventory.CancelReservation(ctx, orderID)
+		_, err := s.transitionOrder(ctx, orderID, models.OrderStatusCanceled, actor)
 		if err != nil {
 			log.Error("failed to cancel reservation", slog.String("error", err.Error()))
 			return fmt.Errorf("%s: %w", op, err)
@@ -172,3 +334,55 @@ func (s *Shop) ConfirmPayment(ctx context.Context, orderID int64, success bool)
 
 	return nil
 }
+
+func (s *Shop) CreateProduct(ctx context.Context, product models.Product) (*models.Product, error) {
+	const op = "shop.CreateProduct"
+
+	log := s.log.With(slog.String("operation", op), slog.String("name", product.Name))
+	log.Info("Starting Create Product")
+
+	created, err := s.writer.CreateProduct(ctx, product)
+	if err != nil {
+		log.Error("CreateProduct failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Create Product done", slog.Int64("productID", created.ProductID))
+	return created, nil
+}
+
+func (s *Shop) UpdateProduct(ctx context.Context, productID int64, update models.ProductUpdate) (*models.Product, error) {
+	const op = "shop.UpdateProduct"
+
+	log := s.log.With(slog.String("operation", op), slog.Int64("productID", productID))
+	log.Info("Starting Update Product")
+
+	product, err := s.writer.UpdateProduct(ctx, productID, update)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			log.Warn("Product not found", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("UpdateProduct failed", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Update Product done")
+	return product, nil
+}
+
+func (s *Shop) DeleteProduct(ctx context.Context, productID int64) error {
+	const op = "shop.DeleteProduct"
+
+	log := s.log.With(slog.String("operation", op), slog.Int64("productID", productID))
+	log.Info("Starting Delete Product")
+
+	if err := s.writer.DeleteProduct(ctx, productID); err != nil {
+		if errors.Is(err, models.ErrProductNotFound) || errors.Is(err, models.ErrProductInUse) {
+			log.Warn("Product cannot be deleted", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("DeleteProduct failed", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("Delete Product done")
+	return nil
+}
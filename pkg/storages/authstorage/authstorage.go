@@ -3,11 +3,25 @@ package authstorage
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/go-redis/redis/v8"
 	_ "github.com/go-redis/redis/v8"
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
 )
 
+// useRefreshTokenScript атомарно помечает refresh-токен использованным.
+// Возвращает счетчик использований, user_id и session_id или nil, если токена нет.
+var useRefreshTokenScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return nil
+end
+local used = redis.call('HINCRBY', KEYS[1], 'used', 1)
+return {used, redis.call('HGET', KEYS[1], 'user_id'), redis.call('HGET', KEYS[1], 'session_id')}
+`)
+
 type StorageUsers struct {
 	client *redis.Client
 }
@@ -33,7 +47,7 @@ func NewUsersStorage(addr, password string, db int) (*StorageUsers, error) {
 	return &StorageUsers{rdb}, err
 }
 
-func (s *StorageUsers) SaveUser(ctx context.Context, email string, passhash []byte) (uid int64, err error) {
+func (s *StorageUsers) SaveUser(ctx context.Context, email string, passhash []byte, roles []models.Role) (uid int64, err error) {
 	const op = "storages.authstorage.SaveUser"
 
 	//Проверяем существование пользователя
@@ -54,6 +68,7 @@ func (s *StorageUsers) SaveUser(ctx context.Context, email string, passhash []by
 		"id":       uid,
 		"email":    email,
 		"passhash": passhash,
+		"roles":    joinRoles(roles),
 	}
 
 	//Используем транзакцию для атомарности
@@ -92,6 +107,241 @@ func (s *StorageUsers) User(ctx context.Context, email string) (models.User, err
 		UserID:   uid,
 		Email:    result["email"],
 		Passhash: []byte(result["passhash"]),
+		Roles:    splitRoles(result["roles"]),
 	}
 	return user, nil
 }
+
+func (s *StorageUsers) UserByID(ctx context.Context, uid int64) (models.User, error) {
+	const op = "storages.authstorage.UserByID"
+
+	result, err := s.client.HGetAll(ctx, fmt.Sprintf("user:%d", uid)).Result()
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s %s", op, err)
+	}
+	if len(result) == 0 {
+		return models.User{}, fmt.Errorf("%s: %w", op, models.ErrUserNotFound)
+	}
+
+	user := models.User{
+		UserID:   uid,
+		Email:    result["email"],
+		Passhash: []byte(result["passhash"]),
+		Roles:    splitRoles(result["roles"]),
+	}
+	return user, nil
+}
+
+func (s *StorageUsers) SetUserRoles(ctx context.Context, uid int64, roles []models.Role) error {
+	const op = "storages.authstorage.SetUserRoles"
+
+	key := fmt.Sprintf("user:%d", uid)
+	if exists, err := s.client.Exists(ctx, key).Result(); err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	} else if exists == 0 {
+		return fmt.Errorf("%s: %w", op, models.ErrUserNotFound)
+	}
+
+	if err := s.client.HSet(ctx, key, "roles", joinRoles(roles)).Err(); err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	return nil
+}
+
+func (s *StorageUsers) CreateSession(ctx context.Context, session models.Session) error {
+	const op = "storages.authstorage.CreateSession"
+
+	key := sessionKey(session.ID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"user_id":    session.UserID,
+			"created_at": session.CreatedAt.Unix(),
+		})
+		pipe.Expire(ctx, key, time.Until(session.ExpiresAt))
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	return nil
+}
+
+func (s *StorageUsers) SessionActive(ctx context.Context, sessionID string) (bool, error) {
+	const op = "storages.authstorage.SessionActive"
+
+	exists, err := s.client.Exists(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("%s %s", op, err)
+	}
+	return exists == 1, nil
+}
+
+func (s *StorageUsers) SaveRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	const op = "storages.authstorage.SaveRefreshToken"
+
+	ttl := time.Until(token.ExpiresAt)
+	tokenKey := refreshTokenKey(token.Hash)
+	tokensKey := sessionRefreshTokensKey(token.SessionID)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, tokenKey, map[string]interface{}{
+			"user_id":    token.UserID,
+			"session_id": token.SessionID,
+			"used":       0,
+		})
+		pipe.Expire(ctx, tokenKey, ttl)
+		//Сессия живет не меньше, чем самый свежий токен в ней
+		pipe.SAdd(ctx, tokensKey, token.Hash)
+		pipe.Expire(ctx, tokensKey, ttl)
+		pipe.Expire(ctx, sessionKey(token.SessionID), ttl)
+		pipe.Expire(ctx, userSessionsKey(token.UserID), ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	return nil
+}
+
+func (s *StorageUsers) UseRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	const op = "storages.authstorage.UseRefreshToken"
+
+	res, err := useRefreshTokenScript.Run(ctx, s.client, []string{refreshTokenKey(tokenHash)}).Slice()
+	if err != nil {
+		if err == redis.Nil {
+			return models.RefreshToken{}, fmt.Errorf("%s: %w", op, models.ErrRefreshTokenNotFound)
+		}
+		return models.RefreshToken{}, fmt.Errorf("%s %s", op, err)
+	}
+	if len(res) != 3 {
+		return models.RefreshToken{}, fmt.Errorf("%s: unexpected script result %v", op, res)
+	}
+
+	used, _ := res[0].(int64)
+	userIDStr, _ := res[1].(string)
+	sessionID, _ := res[2].(string)
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("%s %s", op, err)
+	}
+
+	token := models.RefreshToken{
+		Hash:      tokenHash,
+		UserID:    userID,
+		SessionID: sessionID,
+	}
+	if used > 1 {
+		return token, fmt.Errorf("%s: %w", op, models.ErrRefreshTokenReused)
+	}
+	return token, nil
+}
+
+// RevokeSession удаляет сессию и все ее refresh-токены.
+// Access-токены сессии перестают приниматься сразу, т.к. интерсептор проверяет наличие сессии.
+func (s *StorageUsers) RevokeSession(ctx context.Context, sessionID string) error {
+	const op = "storages.authstorage.RevokeSession"
+
+	userID, err := s.client.HGet(ctx, sessionKey(sessionID), "user_id").Int64()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+
+	tokensKey := sessionRefreshTokensKey(sessionID)
+	hashes, err := s.client.SMembers(ctx, tokensKey).Result()
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+
+	keys := make([]string, 0, len(hashes)+2)
+	for _, hash := range hashes {
+		keys = append(keys, refreshTokenKey(hash))
+	}
+	keys = append(keys, tokensKey, sessionKey(sessionID))
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		if userID != 0 {
+			pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	return nil
+}
+
+// RevokeUserSessions отзывает все сессии пользователя и возвращает их количество
+func (s *StorageUsers) RevokeUserSessions(ctx context.Context, uid int64) (int, error) {
+	const op = "storages.authstorage.RevokeUserSessions"
+
+	sessionIDs, err := s.client.SMembers(ctx, userSessionsKey(uid)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%s %s", op, err)
+	}
+
+	revoked := 0
+	for _, sessionID := range sessionIDs {
+		active, err := s.SessionActive(ctx, sessionID)
+		if err != nil {
+			return revoked, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := s.RevokeSession(ctx, sessionID); err != nil {
+			return revoked, fmt.Errorf("%s: %w", op, err)
+		}
+		if active {
+			revoked++
+		}
+	}
+
+	//Убираем из индекса и записи об истекших сессиях
+	if len(sessionIDs) > 0 {
+		members := make([]interface{}, 0, len(sessionIDs))
+		for _, sessionID := range sessionIDs {
+			members = append(members, sessionID)
+		}
+		if err := s.client.SRem(ctx, userSessionsKey(uid), members...).Err(); err != nil {
+			return revoked, fmt.Errorf("%s %s", op, err)
+		}
+	}
+	return revoked, nil
+}
+
+func joinRoles(roles []models.Role) string {
+	parts := make([]string, 0, len(roles))
+	for _, r := range roles {
+		parts = append(parts, string(r))
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitRoles разбирает поле roles из хэша пользователя.
+// У пользователей, созданных до появления ролей, поля нет - считаем их покупателями.
+func splitRoles(value string) []models.Role {
+	if value == "" {
+		return []models.Role{models.RoleCustomer}
+	}
+	parts := strings.Split(value, ",")
+	roles := make([]models.Role, 0, len(parts))
+	for _, p := range parts {
+		roles = append(roles, models.Role(p))
+	}
+	return roles
+}
+
+func refreshTokenKey(hash string) string {
+	return "refresh:" + hash
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func sessionRefreshTokensKey(sessionID string) string {
+	return "session:" + sessionID + ":refresh"
+}
+
+func userSessionsKey(uid int64) string {
+	return fmt.Sprintf("user:%d:sessions", uid)
+}
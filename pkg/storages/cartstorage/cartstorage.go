@@ -0,0 +1,175 @@
+package cartstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
+)
+
+// cartTTL - брошенные корзины удаляются через месяц после последнего изменения
+const cartTTL = 30 * 24 * time.Hour
+
+// addItemScript атомарно увеличивает количество строки, если новое значение не больше ARGV[3].
+// Возвращает новое количество или -1, если предел превышен; корзина при этом не меняется
+var addItemScript = redis.NewScript(`
+local total = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0') + tonumber(ARGV[2])
+if total > tonumber(ARGV[3]) then
+	return -1
+end
+redis.call('HSET', KEYS[1], ARGV[1], total)
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+return total
+`)
+
+// setQuantityScript атомарно заменяет количество строки, если строка есть и ARGV[2] не больше ARGV[3].
+// Возвращает -2, если строки нет, и -1, если предел превышен; корзина при этом не меняется
+var setQuantityScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return -2
+end
+if tonumber(ARGV[2]) > tonumber(ARGV[3]) then
+	return -1
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+return tonumber(ARGV[2])
+`)
+
+type StorageCarts struct {
+	client *redis.Client
+}
+
+func NewCartStorage(addr, password string, db int) (*StorageCarts, error) {
+	const op = "storages.NewCartStorage"
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &StorageCarts{rdb}, nil
+}
+
+// AddItem добавляет quantity к строке корзины. Если в строке станет больше maxQuantity,
+// возвращает ErrCartQuantityExceeded и ничего не меняет
+func (s *StorageCarts) AddItem(ctx context.Context, userID, productID, variantID int64, quantity, maxQuantity int32) error {
+	const op = "storages.cartstorage.AddItem"
+
+	total, err := addItemScript.Run(ctx, s.client, []string{cartKey(userID)},
+		cartField(productID, variantID), quantity, maxQuantity, int64(cartTTL/time.Second)).Int64()
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	if total < 0 {
+		return fmt.Errorf("%s: %w", op, models.ErrCartQuantityExceeded)
+	}
+	return nil
+}
+
+// SetQuantity заменяет количество в строке корзины. Строка должна уже быть в корзине,
+// а количество не может быть больше maxQuantity
+func (s *StorageCarts) SetQuantity(ctx context.Context, userID, productID, variantID int64, quantity, maxQuantity int32) error {
+	const op = "storages.cartstorage.SetQuantity"
+
+	result, err := setQuantityScript.Run(ctx, s.client, []string{cartKey(userID)},
+		cartField(productID, variantID), quantity, maxQuantity, int64(cartTTL/time.Second)).Int64()
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	switch result {
+	case -2:
+		return fmt.Errorf("%s: %w", op, models.ErrCartItemNotFound)
+	case -1:
+		return fmt.Errorf("%s: %w", op, models.ErrCartQuantityExceeded)
+	}
+	return nil
+}
+
+func (s *StorageCarts) RemoveItem(ctx context.Context, userID, productID, variantID int64) error {
+	const op = "storages.cartstorage.RemoveItem"
+
+	removed, err := s.client.HDel(ctx, cartKey(userID), cartField(productID, variantID)).Result()
+	if err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s: %w", op, models.ErrCartItemNotFound)
+	}
+	return nil
+}
+
+// Items возвращает содержимое корзины, отсортированное по (product_id, variant_id)
+func (s *StorageCarts) Items(ctx context.Context, userID int64) ([]models.CartItem, error) {
+	const op = "storages.cartstorage.Items"
+
+	result, err := s.client.HGetAll(ctx, cartKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s", op, err)
+	}
+
+	items := make([]models.CartItem, 0, len(result))
+	for field, value := range result {
+		productID, variantID, err := parseCartField(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s", op, err)
+		}
+		quantity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s", op, err)
+		}
+		items = append(items, models.CartItem{ProductID: productID, VariantID: variantID, Quantity: int32(quantity)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ProductID != items[j].ProductID {
+			return items[i].ProductID < items[j].ProductID
+		}
+		return items[i].VariantID < items[j].VariantID
+	})
+	return items, nil
+}
+
+func (s *StorageCarts) Clear(ctx context.Context, userID int64) error {
+	const op = "storages.cartstorage.Clear"
+
+	if err := s.client.Del(ctx, cartKey(userID)).Err(); err != nil {
+		return fmt.Errorf("%s %s", op, err)
+	}
+	return nil
+}
+
+func cartKey(userID int64) string {
+	return fmt.Sprintf("cart:%d", userID)
+}
+
+// cartField - поле хэша корзины: "<product_id>" или "<product_id>:<variant_id>" для варианта
+func cartField(productID, variantID int64) string {
+	if variantID == 0 {
+		return strconv.FormatInt(productID, 10)
+	}
+	return fmt.Sprintf("%d:%d", productID, variantID)
+}
+
+func parseCartField(field string) (int64, int64, error) {
+	product, variant, hasVariant := strings.Cut(field, ":")
+	productID, err := strconv.ParseInt(product, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasVariant {
+		return productID, 0, nil
+	}
+	variantID, err := strconv.ParseInt(variant, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return productID, variantID, nil
+}
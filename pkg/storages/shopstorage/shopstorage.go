@@ -3,12 +3,14 @@ package shopstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/kavshevnova/product-reservation-system/pkg/domain/models"
 	"github.com/lib/pq"
-	"log"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,15 +30,69 @@ func NewShopStorage(dsn string) (*StorageProducts, error) {
 	return &StorageProducts{db: db}, nil
 }
 
-func (s *StorageProducts) ListProducts(ctx context.Context, limit, offset int32) ([]models.Product, error) {
+// productSortColumns - разрешенные сортировки каталога: колонка и направление.
+// В SQL попадают только значения из этой таблицы, а не строки из запроса
+var productSortColumns = map[models.ProductSort]struct {
+	column string
+	cast   string
+	desc   bool
+}{
+	models.ProductSortDefault:   {column: "product_id"},
+	models.ProductSortPriceAsc:  {column: "price", cast: "::numeric"},
+	models.ProductSortPriceDesc: {column: "price", cast: "::numeric", desc: true},
+	models.ProductSortNameAsc:   {column: "name", cast: "::text"},
+	models.ProductSortNameDesc:  {column: "name", cast: "::text", desc: true},
+	models.ProductSortNewest:    {column: "product_id", desc: true},
+}
+
+// ListProducts возвращает страницу каталога с учетом фильтра и сортировки:
+// после курсора query.After или со смещением query.Offset
+func (s *StorageProducts) ListProducts(ctx context.Context, query models.ProductQuery) ([]models.Product, error) {
 	const op = "storages.shopstorage.ListProducts"
-	const query = "SELECT product_id, name, price, stock FROM products ORDER BY product_id LIMIT $1 OFFSET $2"
 
+	sort, ok := productSortColumns[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown sort %q", op, query.Sort)
+	}
+
+	limit := query.Limit
 	if limit <= 0 {
 		limit = 10
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+	conditions, args := productConditions(query.Filter)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	direction, compare := "ASC", ">"
+	if sort.desc {
+		direction, compare = "DESC", "<"
+	}
+	if query.After != nil {
+		if sort.column == "product_id" {
+			conditions = append(conditions, fmt.Sprintf("product_id %s %s", compare, arg(query.After.ID)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s, product_id) %s (%s%s, %s)",
+				sort.column, compare, arg(query.After.Value), sort.cast, arg(query.After.ID)))
+		}
+	}
+
+	sqlQuery := "SELECT " + productColumns + " FROM products"
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += " ORDER BY "
+	if sort.column != "product_id" {
+		sqlQuery += sort.column + " " + direction + ", "
+	}
+	sqlQuery += "product_id " + direction + " LIMIT " + arg(limit)
+	if query.After == nil && query.Offset > 0 {
+		sqlQuery += " OFFSET " + arg(query.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -44,11 +100,11 @@ func (s *StorageProducts) ListProducts(ctx context.Context, limit, offset int32)
 
 	var products []models.Product
 	for rows.Next() {
-		var product models.Product
-		if err := rows.Scan(&product.ProductID, &product.Name, &product.Price, &product.Stock); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		products = append(products, product)
+		products = append(products, *product)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -56,27 +112,261 @@ func (s *StorageProducts) ListProducts(ctx context.Context, limit, offset int32)
 	return products, nil
 }
 
+// CountProducts считает товары, подходящие под фильтр, без учета страницы
+func (s *StorageProducts) CountProducts(ctx context.Context, query models.ProductQuery) (int64, error) {
+	const op = "storages.shopstorage.CountProducts"
+
+	conditions, args := productConditions(query.Filter)
+	sqlQuery := "SELECT COUNT(*) FROM products"
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return total, nil
+}
+
+// productConditions переводит фильтр каталога в условия WHERE с плейсхолдерами
+func productConditions(filter models.ProductFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	//Цены в разных валютах не сравниваем: граница фильтра ограничивает и валюту
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "price >= "+arg(*filter.MinPrice)+" AND currency = "+arg(filter.MinPrice.Currency))
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "price <= "+arg(*filter.MaxPrice)+" AND currency = "+arg(filter.MaxPrice.Currency))
+	}
+	if filter.InStockOnly {
+		conditions = append(conditions, productStock+" > 0")
+	}
+	if filter.NameContains != "" {
+		//Экранируем спецсимволы LIKE, чтобы подстрока искалась буквально
+		pattern := likeEscaper.Replace(filter.NameContains)
+		conditions = append(conditions, "name ILIKE '%' || "+arg(pattern)+" || '%'")
+	}
+	if filter.CategoryID != 0 {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM product_categories pc
+			WHERE pc.product_id = products.product_id AND pc.category_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT category_id FROM categories WHERE category_id = `+arg(filter.CategoryID)+`
+					UNION ALL
+					SELECT c.category_id FROM categories c JOIN tree t ON c.parent_id = t.category_id
+				)
+				SELECT category_id FROM tree))`)
+	}
+	return conditions, args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// productStock - остаток товара. Резервации товара с вариантами списывают остатки вариантов,
+// поэтому для него остаток - их сумма, а products.stock не используется
+const productStock = `COALESCE((SELECT SUM(v.stock) FROM product_variants v WHERE v.product_id = products.product_id), products.stock)`
+
+// productColumns - колонки товара в порядке scanProduct
+const productColumns = "product_id, name, price, currency, " + productStock + " AS stock"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner, extra ...any) (*models.Product, error) {
+	var product models.Product
+	dest := append([]any{&product.ProductID, &product.Name, &product.Price, &product.Price.Currency, &product.Stock}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// SearchProducts ищет товары полнотекстовым поиском и сортирует по релевантности
+func (s *StorageProducts) SearchProducts(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error) {
+	const op = "storages.shopstorage.SearchProducts"
+	const sqlQuery = `SELECT ` + productColumns + `,
+			ts_rank(search_vector, q.query) AS rank,
+			ts_headline('english', name, q.query, 'StartSel=<b>, StopSel=</b>, HighlightAll=true') AS snippet
+		FROM products, websearch_to_tsquery('english', $1) AS q(query)
+		WHERE search_vector @@ q.query
+		ORDER BY rank DESC, product_id
+		LIMIT $2`
+
+	hits, err := s.searchHits(ctx, sqlQuery, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return hits, nil
+}
+
+// SearchProductsFuzzy ищет товары по триграммной похожести названия, чтобы находить запросы с опечатками
+func (s *StorageProducts) SearchProductsFuzzy(ctx context.Context, query string, limit int32) ([]models.ProductSearchHit, error) {
+	const op = "storages.shopstorage.SearchProductsFuzzy"
+	const sqlQuery = `SELECT ` + productColumns + `,
+			GREATEST(similarity(name, $1), word_similarity($1, name)) AS rank,
+			name AS snippet
+		FROM products
+		WHERE name % $1 OR $1 <% name
+		ORDER BY rank DESC, product_id
+		LIMIT $2`
+
+	hits, err := s.searchHits(ctx, sqlQuery, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for i := range hits {
+		hits[i].Fuzzy = true
+	}
+	return hits, nil
+}
+
+func (s *StorageProducts) searchHits(ctx context.Context, sqlQuery, query string, limit int32) ([]models.ProductSearchHit, error) {
+	rows, err := s.db.QueryContext(ctx, sqlQuery, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []models.ProductSearchHit
+	for rows.Next() {
+		var hit models.ProductSearchHit
+		product, err := scanProduct(rows, &hit.Rank, &hit.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		hit.Product = *product
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// Categories возвращает все категории каталога
+func (s *StorageProducts) Categories(ctx context.Context) ([]models.Category, error) {
+	const op = "storages.shopstorage.Categories"
+	const query = "SELECT category_id, COALESCE(parent_id, 0) AS parent_id, name, slug FROM categories ORDER BY name, category_id"
+
+	var categories []models.Category
+	if err := s.db.SelectContext(ctx, &categories, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return categories, nil
+}
+
+// Product возвращает товар вместе с вариантами
 func (s *StorageProducts) Product(ctx context.Context, productID int64) (*models.Product, error) {
 	const op = "storages.shopstorage.Product"
-	const query = "SELECT product_id, name, price, stock FROM products WHERE product_id = $1"
+	const query = "SELECT " + productColumns + " FROM products WHERE product_id = $1"
 
-	var product models.Product
-	err := s.db.GetContext(ctx, &product, query, productID)
+	product, err := scanProduct(s.db.QueryRowContext(ctx, query, productID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT v.variant_id, v.sku, v.attributes, COALESCE(v.price, p.price), v.stock
+		FROM product_variants v JOIN products p ON p.product_id = v.product_id
+		WHERE v.product_id = $1 ORDER BY v.variant_id`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant := models.ProductVariant{ProductID: productID, Price: models.Money{Currency: product.Price.Currency}}
+		var attributes []byte
+		if err := rows.Scan(&variant.ID, &variant.SKU, &attributes, &variant.Price, &variant.Stock); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal(attributes, &variant.Attributes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		product.Variants = append(product.Variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return product, nil
+}
+
+func (s *StorageProducts) CreateProduct(ctx context.Context, product models.Product) (*models.Product, error) {
+	const op = "storages.shopstorage.CreateProduct"
+	const query = "INSERT INTO products (name, price, currency, stock) VALUES ($1, $2, $3, $4) RETURNING " + productColumns
+
+	currency := product.Price.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	created, err := scanProduct(s.db.QueryRowContext(ctx, query, product.Name, product.Price, currency, product.Stock))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
 
+func (s *StorageProducts) UpdateProduct(ctx context.Context, productID int64, update models.ProductUpdate) (*models.Product, error) {
+	const op = "storages.shopstorage.UpdateProduct"
+	//NULL в параметре оставляет поле без изменений
+	const query = `UPDATE products SET
+		name = COALESCE($2, name),
+		price = COALESCE($3, price),
+		currency = COALESCE($4, currency),
+		stock = COALESCE($5, stock)
+		WHERE product_id = $1
+		RETURNING ` + productColumns
+
+	var currency *string
+	if update.Price != nil && update.Price.Currency != "" {
+		currency = &update.Price.Currency
+	}
+	product, err := scanProduct(s.db.QueryRowContext(ctx, query, productID, update.Name, update.Price, currency, update.Stock))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrProductNotFound
 		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return &product, nil
+	return product, nil
+}
+
+func (s *StorageProducts) DeleteProduct(ctx context.Context, productID int64) error {
+	const op = "storages.shopstorage.DeleteProduct"
+	const query = "DELETE FROM products WHERE product_id = $1"
+
+	res, err := s.db.ExecContext(ctx, query, productID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return models.ErrProductInUse
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return models.ErrProductNotFound
+	}
+	return nil
 }
 
-func (s *StorageProducts) ReserveProduct(ctx context.Context, userID, productID int64, quantity int32) (*models.Order, error) {
+// ReserveProduct резервирует все строки заказа в одной транзакции: либо все, либо ничего.
+// Товары блокируются по возрастанию product_id, чтобы параллельные заказы не ловили deadlock.
+func (s *StorageProducts) ReserveProduct(ctx context.Context, userID int64, items []models.OrderItem, expiresAt time.Time) (*models.Order, error) {
 	const op = "storages.shopstorage.ReserveProduct"
 
-	log.Printf("Reserving product for user %d, product %d, quantity %d",
-		userID, productID, quantity)
+	items = mergeOrderItems(items)
+	if len(items) == 0 {
+		return nil, models.ErrEmptyOrder
+	}
 
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -84,57 +374,147 @@ func (s *StorageProducts) ReserveProduct(ctx context.Context, userID, productID
 	}
 	defer tx.Rollback()
 
-	//Проверяем и блокируем товар
-	var price float64
-	var stock int32
-	err = tx.QueryRowContext(ctx, `SELECT price, stock FROM products WHERE product_id = $1 FOR UPDATE`, productID).Scan(&price, &stock)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, models.ErrProductNotFound
+	//Проверяем и блокируем товары, собирая все проблемные строки.
+	//Строки отсортированы по (product_id, variant_id), поэтому блокировки берутся в одном порядке
+	var sum models.Money
+	var stockErr models.StockError
+	for i := range items {
+		var price models.Money
+		var stock int32
+		var hasVariants bool
+		err = tx.QueryRowContext(ctx, `SELECT name, price, currency, stock, EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.product_id)
+			FROM products WHERE product_id = $1 FOR UPDATE`, items[i].ProductID).Scan(&items[i].ProductName, &price, &price.Currency, &stock, &hasVariants)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				stockErr.Problems = append(stockErr.Problems, stockProblem(items[i], 0, models.ErrProductNotFound))
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		//Для товара с вариантами цена и остаток берутся из варианта
+		switch {
+		case items[i].VariantID != 0:
+			err = tx.QueryRowContext(ctx, `SELECT v.sku, COALESCE(v.price, p.price), v.stock
+				FROM product_variants v JOIN products p ON p.product_id = v.product_id
+				WHERE v.variant_id = $1 AND v.product_id = $2 FOR UPDATE OF v`, items[i].VariantID, items[i].ProductID).Scan(&items[i].SKU, &price, &stock)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					stockErr.Problems = append(stockErr.Problems, stockProblem(items[i], 0, models.ErrVariantNotFound))
+					continue
+				}
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		case hasVariants:
+			stockErr.Problems = append(stockErr.Problems, stockProblem(items[i], 0, models.ErrVariantRequired))
+			continue
+		}
+
+		if stock < items[i].Quantity {
+			stockErr.Problems = append(stockErr.Problems, stockProblem(items[i], stock, models.ErrNotEnoughStock))
+			continue
+		}
+		//Считаем в копейках, чтобы не терять точность на больших количествах
+		items[i].Price = price
+		items[i].Sum, err = price.Mul(items[i].Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		//В одном заказе все строки должны быть в одной валюте
+		sum, err = sum.Add(items[i].Sum)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if stock < quantity {
-		return nil, models.ErrNotEnoughStock
+	if len(stockErr.Problems) > 0 {
+		return nil, &stockErr
 	}
 
-	//Создаем резервацию
-	sum := price * float64(quantity)
+	//Создаем резервацию. Для заказа из одной строки дублируем товар в orders для старых клиентов
+	var productID sql.NullInt64
+	var quantity sql.NullInt32
+	if len(items) == 1 {
+		productID = sql.NullInt64{Int64: items[0].ProductID, Valid: true}
+		quantity = sql.NullInt32{Int32: items[0].Quantity, Valid: true}
+	}
 	var orderID int64
 	now := time.Now()
-	err = tx.QueryRowContext(ctx, `INSERT INTO orders (user_id, product_id, quantity, sum, status, time) VALUES ($1, $2, $3, $4, 'reserved', $5) RETURNING order_id`, userID, productID, quantity, sum, now).Scan(&orderID)
+	err = tx.QueryRowContext(ctx, `INSERT INTO orders (user_id, product_id, quantity, sum, currency, status, time, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING order_id`, userID, productID, quantity, sum, sum.Currency, models.OrderStatusReserved, now, expiresAt).Scan(&orderID)
 	if err != nil {
 		if isDuplicateKeyError(err) {
 			return nil, models.ErrOrderAlreadyExists
 		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	if err := insertStatusHistory(ctx, tx, orderID, "", models.OrderStatusReserved, models.UserActor(userID)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	//Обновляем остатки
-	_, err = tx.ExecContext(ctx, `UPDATE products SET stock = stock - $1 WHERE product_id = $2`, quantity, productID)
-	if err != nil {
+	for _, item := range items {
+		var variantID sql.NullInt64
+		var sku sql.NullString
+		if item.VariantID != 0 {
+			variantID = sql.NullInt64{Int64: item.VariantID, Valid: true}
+			sku = sql.NullString{String: item.SKU, Valid: true}
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO order_items (order_id, product_id, variant_id, sku, product_name, quantity, price, sum) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			orderID, item.ProductID, variantID, sku, item.ProductName, item.Quantity, item.Price, item.Sum)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		//Обновляем остатки: у варианта или у самого товара
+		if item.VariantID != 0 {
+			_, err = tx.ExecContext(ctx, `UPDATE product_variants SET stock = stock - $1 WHERE variant_id = $2`, item.Quantity, item.VariantID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE products SET stock = stock - $1 WHERE product_id = $2`, item.Quantity, item.ProductID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	event := models.OrderEvent{
+		OrderID:    orderID,
+		UserID:     userID,
+		Status:     models.OrderStatusReserved,
+		Amount:     sum.Amount,
+		Currency:   sum.Currency,
+		Actor:      models.UserActor(userID),
+		OccurredAt: now,
+	}
+	for _, item := range items {
+		event.Items = append(event.Items, models.EventItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+	if err := insertOutboxEvent(ctx, tx, models.EventOrderReserved, event); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	if err := insertOutboxEvent(ctx, tx, models.EventInventoryReserved, event); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return &models.Order{
 		ID:        orderID,
-		ProductID: productID,
+		ProductID: productID.Int64,
 		UserID:    userID,
-		Quantity:  quantity,
-		Sum:       float32(sum),
-		Status:    "reserved",
+		Quantity:  quantity.Int32,
+		Sum:       sum,
+		Status:    models.OrderStatusReserved,
 		Time:      now,
+		ExpiresAt: expiresAt,
+		Items:     items,
 	}, nil
 }
 
-func (s *StorageProducts) ConfirmOrder(ctx context.Context, orderID int64) (*models.Order, error) {
-	const op = "storages.shopstorage.ConfirmOrder"
-	const query = "UPDATE orders SET status = 'confirmed' WHERE order_id = $1 AND status = 'reserved' RETURNING order_id, user_id, product_id, quantity, sum, status, time"
+func (s *StorageProducts) Order(ctx context.Context, orderID int64) (*models.Order, error) {
+	const op = "storages.shopstorage.Order"
+	const query = "SELECT order_id, user_id, COALESCE(product_id, 0), COALESCE(quantity, 0), sum, currency, status, time FROM orders WHERE order_id = $1"
 
 	var order models.Order
-	err := s.db.QueryRowContext(ctx, query, orderID).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Status, &order.Time)
+	err := s.db.QueryRowContext(ctx, query, orderID).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Sum.Currency, &order.Status, &order.Time)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrOrderNotFound
@@ -144,46 +524,202 @@ func (s *StorageProducts) ConfirmOrder(ctx context.Context, orderID int64) (*mod
 	return &order, nil
 }
 
-func (s *StorageProducts) CancelReservation(ctx context.Context, orderID int64) error {
+// ConfirmOrder переводит оплаченную резервацию в статус paid
+func (s *StorageProducts) ConfirmOrder(ctx context.Context, t models.OrderTransition) (*models.Order, error) {
+	const op = "storages.shopstorage.ConfirmOrder"
+	const query = "UPDATE orders SET status = $3 WHERE order_id = $1 AND status = $2 RETURNING order_id, user_id, COALESCE(product_id, 0), COALESCE(quantity, 0), sum, currency, status, time"
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var order models.Order
+	err = tx.QueryRowContext(ctx, query, t.OrderID, t.From, t.To).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Sum.Currency, &order.Status, &order.Time)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidTransition
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := insertStatusHistory(ctx, tx, t.OrderID, t.From, t.To, t.Actor); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	err = insertOutboxEvent(ctx, tx, models.EventOrderPaid, models.OrderEvent{
+		OrderID:    order.ID,
+		UserID:     order.UserID,
+		From:       t.From,
+		Status:     t.To,
+		Amount:     order.Sum.Amount,
+		Currency:   order.Sum.Currency,
+		Actor:      t.Actor,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &order, nil
+}
+
+// CancelReservation отменяет заказ и возвращает товар на склад.
+// Используется для отмены (canceled) и истечения (expired) резервации, а также для отмены
+// оплаченного заказа: тогда в той же транзакции создается заявка на возврат денег
+func (s *StorageProducts) CancelReservation(ctx context.Context, t models.OrderTransition) (*models.Refund, error) {
 	const op = "storages.shopstorage.CancelReservation"
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer tx.Rollback()
 
-	//Получаем информацию о резервации
-	var productID int64
-	var quantity int32
-	err = tx.QueryRowContext(ctx, `SELECT product_id, quantity FROM orders WHERE order_id = $1 AND status = 'reserved' FOR UPDATE`, orderID).Scan(&productID, &quantity)
+	//Блокируем заказ и проверяем, что статус не изменился
+	var current models.OrderStatus
+	var userID int64
+	var sum models.Money
+	err = tx.QueryRowContext(ctx, `SELECT status, user_id, sum, currency FROM orders WHERE order_id = $1 FOR UPDATE`, t.OrderID).Scan(&current, &userID, &sum, &sum.Currency)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.ErrOrderNotFound
+			return nil, models.ErrOrderNotFound
 		}
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if current != t.From {
+		return nil, models.ErrInvalidTransition
 	}
 
 	//Возвращаем товар на склад
-	_, err = tx.ExecContext(ctx, `UPDATE products SET quantity = quantity + $1 WHERE product_id = $2`, quantity, productID)
+	if err := restockOrderItems(ctx, tx, t.OrderID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	//Отменяем заказ
+	if err := changeOrderStatus(ctx, tx, t); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	//За оплаченный заказ нужно вернуть деньги
+	var refund *models.Refund
+	if current == models.OrderStatusPaid {
+		refund = &models.Refund{
+			OrderID: t.OrderID,
+			Amount:  sum,
+			Status:  models.RefundStatusRequested,
+			Reason:  t.Reason,
+		}
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO refunds (order_id, amount, status, reason) VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+			refund.OrderID, refund.Amount, refund.Status, refund.Reason,
+		).Scan(&refund.ID, &refund.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	event := models.OrderEvent{
+		OrderID:    t.OrderID,
+		UserID:     userID,
+		From:       t.From,
+		Status:     t.To,
+		Amount:     sum.Amount,
+		Currency:   sum.Currency,
+		Actor:      t.Actor,
+		Reason:     t.Reason,
+		OccurredAt: time.Now(),
+	}
+	event.Items, err = orderEventItems(ctx, tx, t.OrderID)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	eventType := models.EventOrderCanceled
+	if t.To == models.OrderStatusExpired {
+		eventType = models.EventOrderExpired
+	}
+	if err := insertOutboxEvent(ctx, tx, eventType, event); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	if err := insertOutboxEvent(ctx, tx, models.EventInventoryReleased, event); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return refund, nil
+}
 
-	//Отменяем резервацию
-	_, err = tx.ExecContext(ctx, `UPDATE orders SET status = 'canceled' WHERE order_id = $1`, orderID)
+// UpdateOrderStatus меняет статус без движения товара (отгрузка, доставка, возврат денег)
+func (s *StorageProducts) UpdateOrderStatus(ctx context.Context, t models.OrderTransition) error {
+	const op = "storages.shopstorage.UpdateOrderStatus"
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
+
+	if err := changeOrderStatus(ctx, tx, t); err != nil {
+		if errors.Is(err, models.ErrInvalidTransition) {
+			return models.ErrInvalidTransition
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
-func (s *StorageProducts) GetOrderHistory(ctx context.Context, userID int64) ([]models.Order, error) {
+// ExpiredReservations возвращает id резерваций, срок оплаты которых истек
+func (s *StorageProducts) ExpiredReservations(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	const op = "storages.shopstorage.ExpiredReservations"
+	const query = "SELECT order_id FROM orders WHERE status = 'reserved' AND expires_at <= $1 ORDER BY expires_at LIMIT $2"
+
+	var ids []int64
+	if err := s.db.SelectContext(ctx, &ids, query, now, limit); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ids, nil
+}
+
+// GetOrderHistory возвращает заказы пользователя по убыванию (time, order_id), начиная после filter.After
+func (s *StorageProducts) GetOrderHistory(ctx context.Context, filter models.OrderHistoryFilter) ([]models.Order, error) {
 	const op = "storages.shopstorage.OrderHistory"
-	const query = "SELECT order_id, user_id, product_id, quantity, sum, status, time FROM orders WHERE user_id = $1 ORDER BY time DESC"
-	rows, err := s.db.QueryContext(ctx, query, userID)
+
+	//Условия собираются только из плейсхолдеров, значения идут отдельно
+	conditions := []string{"user_id = $1"}
+	args := []any{filter.UserID}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(time, order_id) < (%s, %s)", arg(filter.After.Time), arg(filter.After.ID)))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		conditions = append(conditions, fmt.Sprintf("status = ANY(%s)", arg(pq.Array(statuses))))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("time >= %s", arg(filter.From)))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("time < %s", arg(filter.To)))
+	}
+	if filter.ProductID != 0 {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = orders.order_id AND oi.product_id = %s)", arg(filter.ProductID)))
+	}
+
+	query := "SELECT order_id, user_id, COALESCE(product_id, 0), COALESCE(quantity, 0), sum, currency, status, time FROM orders WHERE " +
+		strings.Join(conditions, " AND ") +
+		" ORDER BY time DESC, order_id DESC LIMIT " + arg(filter.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -192,22 +728,922 @@ func (s *StorageProducts) GetOrderHistory(ctx context.Context, userID int64) ([]
 	var orders []models.Order
 	for rows.Next() {
 		var order models.Order
-		var time sql.NullTime
-		if err := rows.Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Status, &time); err != nil {
+		if err := rows.Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Sum.Currency, &order.Status, &order.Time); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		if time.Valid {
-			order.Time = time.Time
-		}
 		orders = append(orders, order)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.loadOrderItems(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	//Возвраты нужны истории, чтобы показать возвращенную сумму
+	if err := s.loadOrderRefunds(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	return orders, nil
 }
 
-func isDuplicateKeyError(err error) bool {
-	var pgErr *pq.Error
-	if errors.As(err, &pgErr) {
-		return pgErr.Code == "23505"
+// OrderDetails возвращает заказ со строками, историей статусов и возвратами
+func (s *StorageProducts) OrderDetails(ctx context.Context, orderID int64) (*models.Order, error) {
+	const op = "storages.shopstorage.OrderDetails"
+	const query = `SELECT order_id, user_id, COALESCE(product_id, 0), COALESCE(quantity, 0), sum, currency, status, time, expires_at
+		FROM orders WHERE order_id = $1`
+
+	var order models.Order
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, query, orderID).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.Sum, &order.Sum.Currency, &order.Status, &order.Time, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return false
+	if expiresAt.Valid {
+		order.ExpiresAt = expiresAt.Time
+	}
+
+	orders := []models.Order{order}
+	if err := s.loadOrderItems(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order = orders[0]
+
+	rows, err := s.db.QueryContext(ctx, `SELECT COALESCE(from_status, ''), to_status, actor, time
+		FROM order_status_history WHERE order_id = $1 ORDER BY time, id`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var change models.OrderStatusChange
+		if err := rows.Scan(&change.From, &change.To, &change.Actor, &change.Time); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		order.StatusHistory = append(order.StatusHistory, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders = []models.Order{order}
+	if err := s.loadOrderRefunds(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order = orders[0]
+
+	order.Payments, err = s.loadPayments(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	order.Returns, err = s.loadReturns(ctx, "r.order_id = $1", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &order, nil
+}
+
+// loadOrderItems подгружает строки для списка заказов одним запросом
+func (s *StorageProducts) loadOrderItems(ctx context.Context, orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(orders))
+	index := make(map[int64]int, len(orders))
+	for i, order := range orders {
+		ids = append(ids, order.ID)
+		index[order.ID] = i
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT order_id, product_id, COALESCE(variant_id, 0), COALESCE(sku, ''), product_name, quantity, price, sum
+		FROM order_items WHERE order_id = ANY($1) ORDER BY order_item_id`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int64
+		var item models.OrderItem
+		if err := rows.Scan(&orderID, &item.ProductID, &item.VariantID, &item.SKU, &item.ProductName, &item.Quantity, &item.Price, &item.Sum); err != nil {
+			return err
+		}
+		i := index[orderID]
+		item.Price.Currency = orders[i].Sum.Currency
+		item.Sum.Currency = orders[i].Sum.Currency
+		orders[i].Items = append(orders[i].Items, item)
+	}
+	return rows.Err()
+}
+
+// changeOrderStatus меняет статус, только если он все еще равен t.From, и пишет историю
+func changeOrderStatus(ctx context.Context, tx *sqlx.Tx, t models.OrderTransition) error {
+	res, err := tx.ExecContext(ctx, `UPDATE orders SET status = $3 WHERE order_id = $1 AND status = $2`, t.OrderID, t.From, t.To)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrInvalidTransition
+	}
+	return insertStatusHistory(ctx, tx, t.OrderID, t.From, t.To, t.Actor)
+}
+
+func insertStatusHistory(ctx context.Context, tx *sqlx.Tx, orderID int64, from, to models.OrderStatus, actor string) error {
+	var fromStatus sql.NullString
+	if from != "" {
+		fromStatus = sql.NullString{String: string(from), Valid: true}
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO order_status_history (order_id, from_status, to_status, actor) VALUES ($1, $2, $3, $4)`, orderID, fromStatus, to, actor)
+	return err
+}
+
+// restockOrderItems возвращает на склад товары и варианты заказа.
+// Строки блокируются в том же порядке, что и в ReserveProduct.
+func restockOrderItems(ctx context.Context, tx *sqlx.Tx, orderID int64) error {
+	_, err := tx.ExecContext(ctx, `SELECT p.product_id FROM products p
+		JOIN order_items oi ON oi.product_id = p.product_id
+		WHERE oi.order_id = $1
+		ORDER BY p.product_id
+		FOR UPDATE OF p`, orderID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT v.variant_id FROM product_variants v
+		JOIN order_items oi ON oi.variant_id = v.variant_id
+		WHERE oi.order_id = $1
+		ORDER BY v.product_id, v.variant_id
+		FOR UPDATE OF v`, orderID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE products p SET stock = p.stock + oi.quantity
+		FROM order_items oi
+		WHERE oi.order_id = $1 AND p.product_id = oi.product_id AND oi.variant_id IS NULL`, orderID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE product_variants v SET stock = v.stock + oi.quantity
+		FROM order_items oi
+		WHERE oi.order_id = $1 AND v.variant_id = oi.variant_id`, orderID)
+	return err
+}
+
+// mergeOrderItems складывает количество одинаковых товаров и сортирует строки по product_id
+func mergeOrderItems(items []models.OrderItem) []models.OrderItem {
+	type lineKey struct{ productID, variantID int64 }
+	byLine := make(map[lineKey]int32, len(items))
+	for _, item := range items {
+		byLine[lineKey{item.ProductID, item.VariantID}] += item.Quantity
+	}
+	merged := make([]models.OrderItem, 0, len(byLine))
+	for key, quantity := range byLine {
+		merged = append(merged, models.OrderItem{ProductID: key.productID, VariantID: key.variantID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].ProductID != merged[j].ProductID {
+			return merged[i].ProductID < merged[j].ProductID
+		}
+		return merged[i].VariantID < merged[j].VariantID
+	})
+	return merged
+}
+
+func stockProblem(item models.OrderItem, available int32, reason error) models.StockProblem {
+	return models.StockProblem{
+		ProductID: item.ProductID,
+		VariantID: item.VariantID,
+		Requested: item.Quantity,
+		Available: available,
+		Reason:    reason,
+	}
+}
+
+// idempotencyLease - сколько ключ без ответа считается занятым выполняющимся запросом.
+// Аренда на порядки дольше дедлайна RPC: занять ключ заново можно, только если процесс,
+// который его держал, точно умер, иначе повтор выполнил бы операцию второй раз
+const idempotencyLease = time.Hour
+
+// StartIdempotentRequest занимает ключ идемпотентности.
+// Возвращает nil, если ключ свободен и теперь принадлежит вызывающему, иначе - существующую запись.
+// Ключ, зависший без ответа дольше idempotencyLease (например, после падения процесса), можно занять заново.
+func (s *StorageProducts) StartIdempotentRequest(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	const op = "storages.shopstorage.StartIdempotentRequest"
+	const insert = `INSERT INTO idempotency_keys (user_id, method, key, fingerprint) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, method, key) DO UPDATE
+			SET fingerprint = EXCLUDED.fingerprint, created_at = CURRENT_TIMESTAMP
+			WHERE idempotency_keys.response IS NULL
+				AND idempotency_keys.created_at < CURRENT_TIMESTAMP - make_interval(secs => $5)
+		RETURNING user_id`
+	const selectExisting = "SELECT fingerprint, response FROM idempotency_keys WHERE user_id = $1 AND method = $2 AND key = $3"
+
+	var userID int64
+	err := s.db.QueryRowContext(ctx, insert, record.UserID, record.Method, record.Key, record.Fingerprint, idempotencyLease.Seconds()).Scan(&userID)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing := record
+	err = s.db.QueryRowContext(ctx, selectExisting, record.UserID, record.Method, record.Key).Scan(&existing.Fingerprint, &existing.Response)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &existing, nil
+}
+
+func (s *StorageProducts) SaveIdempotentResponse(ctx context.Context, userID int64, method, key string, response []byte) error {
+	const op = "storages.shopstorage.SaveIdempotentResponse"
+	const query = "UPDATE idempotency_keys SET response = $4 WHERE user_id = $1 AND method = $2 AND key = $3"
+
+	if _, err := s.db.ExecContext(ctx, query, userID, method, key, response); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey освобождает ключ после неудачного запроса, чтобы клиент мог повторить его
+func (s *StorageProducts) ReleaseIdempotencyKey(ctx context.Context, userID int64, method, key string) error {
+	const op = "storages.shopstorage.ReleaseIdempotencyKey"
+	const query = "DELETE FROM idempotency_keys WHERE user_id = $1 AND method = $2 AND key = $3 AND response IS NULL"
+
+	if _, err := s.db.ExecContext(ctx, query, userID, method, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys удаляет пачку ключей, созданных раньше before, и возвращает их количество
+func (s *StorageProducts) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error) {
+	const op = "storages.shopstorage.DeleteExpiredIdempotencyKeys"
+	const query = `DELETE FROM idempotency_keys WHERE (user_id, method, key) IN (
+		SELECT user_id, method, key FROM idempotency_keys WHERE created_at < $1 LIMIT $2)`
+
+	result, err := s.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return int(deleted), nil
+}
+
+func isDuplicateKeyError(err error) bool {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return false
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503"
+	}
+	return false
+}
+
+// SaveWebhookEvent запоминает событие платежного вебхука. false - событие уже приходило
+func (s *StorageProducts) SaveWebhookEvent(ctx context.Context, provider, eventID string) (bool, error) {
+	const op = "storages.shopstorage.SaveWebhookEvent"
+	const query = `INSERT INTO payment_webhook_events (provider, event_id) VALUES ($1, $2)
+		ON CONFLICT (provider, event_id) DO NOTHING`
+
+	result, err := s.db.ExecContext(ctx, query, provider, eventID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return inserted == 1, nil
+}
+
+// ReleaseWebhookEvent забывает событие, которое не удалось обработать, чтобы провайдер мог прислать его повторно
+func (s *StorageProducts) ReleaseWebhookEvent(ctx context.Context, provider, eventID string) error {
+	const op = "storages.shopstorage.ReleaseWebhookEvent"
+	const query = "DELETE FROM payment_webhook_events WHERE provider = $1 AND event_id = $2"
+
+	if _, err := s.db.ExecContext(ctx, query, provider, eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+const paymentColumns = "id, order_id, provider, external_id, payment_url, amount, currency, status, attempts, raw_payload, created_at, updated_at"
+
+func scanPayment(row rowScanner) (*models.Payment, error) {
+	var payment models.Payment
+	var raw []byte
+	err := row.Scan(&payment.ID, &payment.OrderID, &payment.Provider, &payment.ExternalID, &payment.URL,
+		&payment.Amount, &payment.Amount.Currency, &payment.Status, &payment.Attempts, &raw, &payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	payment.RawPayload = raw
+	return &payment, nil
+}
+
+// rawPayload - ответ провайдера для колонки JSONB; пустой ответ сохраняется как NULL
+func rawPayload(raw []byte) sql.NullString {
+	return sql.NullString{String: string(raw), Valid: len(raw) > 0}
+}
+
+// SavePayment записывает открытую у провайдера сессию. Повторная запись той же сессии ничего не меняет
+func (s *StorageProducts) SavePayment(ctx context.Context, session models.PaymentSession) (*models.Payment, error) {
+	const op = "storages.shopstorage.SavePayment"
+	query := `INSERT INTO payments (order_id, provider, external_id, payment_url, amount, currency, status, raw_payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (provider, external_id) DO UPDATE SET updated_at = payments.updated_at
+		RETURNING ` + paymentColumns
+
+	payment, err := scanPayment(s.db.QueryRowContext(ctx, query, session.OrderID, session.Provider, session.ExternalID,
+		session.URL, session.Amount, session.Amount.Currency, session.Status, rawPayload(session.RawPayload)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return payment, nil
+}
+
+// RecordPaymentAttempt сохраняет итог попытки оплаты: новый статус, счетчик попыток и ответ провайдера.
+// Без ExternalID обновляется последний платеж заказа
+func (s *StorageProducts) RecordPaymentAttempt(ctx context.Context, result models.PaymentResult) (*models.Payment, error) {
+	const op = "storages.shopstorage.RecordPaymentAttempt"
+	query := `UPDATE payments SET status = $3, attempts = attempts + 1,
+			raw_payload = COALESCE($4::jsonb, raw_payload), updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM payments WHERE order_id = $1 AND ($2::text = '' OR external_id = $2::text)
+			ORDER BY id DESC LIMIT 1
+		)
+		RETURNING ` + paymentColumns
+
+	payment, err := scanPayment(s.db.QueryRowContext(ctx, query, result.OrderID, result.ExternalID, result.Status(), rawPayload(result.RawPayload)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, models.ErrPaymentNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return payment, nil
+}
+
+func (s *StorageProducts) loadPayments(ctx context.Context, orderID int64) ([]models.Payment, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE order_id = $1 ORDER BY id", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *payment)
+	}
+	return payments, rows.Err()
+}
+
+// CreateReturn записывает заявку на возврат. Заказ блокируется, чтобы две заявки
+// не вернули в сумме больше, чем было куплено
+func (s *StorageProducts) CreateReturn(ctx context.Context, ret models.Return) (*models.Return, error) {
+	const op = "storages.shopstorage.CreateReturn"
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var status models.OrderStatus
+	var currency string
+	err = tx.QueryRowContext(ctx, `SELECT status, currency FROM orders WHERE order_id = $1 FOR UPDATE`, ret.OrderID).Scan(&status, &currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !status.Returnable() {
+		return nil, models.ErrOrderNotReturnable
+	}
+
+	//Сколько куплено и сколько уже заявлено к возврату по каждой строке
+	type lineKey struct{ productID, variantID int64 }
+	type line struct {
+		price    models.Money
+		ordered  int32
+		returned int32
+	}
+	lines := make(map[lineKey]*line)
+	rows, err := tx.QueryContext(ctx, `SELECT product_id, COALESCE(variant_id, 0), price, quantity
+		FROM order_items WHERE order_id = $1`, ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var key lineKey
+		l := &line{price: models.Money{Currency: currency}}
+		if err := rows.Scan(&key.productID, &key.variantID, &l.price, &l.ordered); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		lines[key] = l
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err = tx.QueryContext(ctx, `SELECT ri.product_id, COALESCE(ri.variant_id, 0), SUM(ri.quantity)
+		FROM order_return_items ri JOIN order_returns r ON r.id = ri.return_id
+		WHERE r.order_id = $1
+		GROUP BY ri.product_id, COALESCE(ri.variant_id, 0)`, ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var key lineKey
+		var returned int32
+		if err := rows.Scan(&key.productID, &key.variantID, &returned); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if l, ok := lines[key]; ok {
+			l.returned = returned
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ret.Items = mergeReturnItems(ret.Items)
+	ret.Amount = models.Money{Currency: currency}
+	for _, item := range ret.Items {
+		l, ok := lines[lineKey{item.ProductID, item.VariantID}]
+		if !ok || l.returned+item.Quantity > l.ordered {
+			return nil, models.ErrReturnExceedsOrder
+		}
+		lineAmount, err := l.price.Mul(item.Quantity)
+		if err == nil {
+			ret.Amount, err = ret.Amount.Add(lineAmount)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	ret.Status = models.ReturnStatusRequested
+	err = tx.QueryRowContext(ctx, `INSERT INTO order_returns (order_id, status, reason, amount)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`,
+		ret.OrderID, ret.Status, ret.Reason, ret.Amount,
+	).Scan(&ret.ID, &ret.CreatedAt, &ret.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, item := range ret.Items {
+		var variantID sql.NullInt64
+		if item.VariantID != 0 {
+			variantID = sql.NullInt64{Int64: item.VariantID, Valid: true}
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO order_return_items (return_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)`,
+			ret.ID, item.ProductID, variantID, item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &ret, nil
+}
+
+// ApproveReturn одобряет заявку и, если нужно, возвращает ее товары на склад в той же транзакции
+func (s *StorageProducts) ApproveReturn(ctx context.Context, returnID int64, restock bool) (*models.Return, error) {
+	const op = "storages.shopstorage.ApproveReturn"
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var status models.ReturnStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM order_returns WHERE id = $1 FOR UPDATE`, returnID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrReturnNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if status != models.ReturnStatusRequested {
+		return nil, models.ErrReturnNotApprovable
+	}
+
+	if restock {
+		if err := restockReturnItems(ctx, tx, returnID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE order_returns SET status = $2, restocked = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		returnID, models.ReturnStatusApproved, restock)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.Return(ctx, returnID)
+}
+
+func (s *StorageProducts) Return(ctx context.Context, returnID int64) (*models.Return, error) {
+	const op = "storages.shopstorage.Return"
+
+	returns, err := s.loadReturns(ctx, "r.id = $1", returnID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(returns) == 0 {
+		return nil, models.ErrReturnNotFound
+	}
+	return &returns[0], nil
+}
+
+// loadReturns загружает заявки на возврат со строками по условию на order_returns r
+func (s *StorageProducts) loadReturns(ctx context.Context, condition string, args ...any) ([]models.Return, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT r.id, r.order_id, r.status, r.reason, r.restocked, r.amount, o.currency, r.created_at, r.updated_at
+		FROM order_returns r JOIN orders o ON o.order_id = r.order_id
+		WHERE `+condition+` ORDER BY r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var returns []models.Return
+	index := make(map[int64]int)
+	for rows.Next() {
+		var ret models.Return
+		if err := rows.Scan(&ret.ID, &ret.OrderID, &ret.Status, &ret.Reason, &ret.Restocked, &ret.Amount, &ret.Amount.Currency, &ret.CreatedAt, &ret.UpdatedAt); err != nil {
+			return nil, err
+		}
+		index[ret.ID] = len(returns)
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(returns) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, 0, len(returns))
+	for _, ret := range returns {
+		ids = append(ids, ret.ID)
+	}
+	itemRows, err := s.db.QueryContext(ctx, `SELECT return_id, product_id, COALESCE(variant_id, 0), quantity
+		FROM order_return_items WHERE return_id = ANY($1) ORDER BY product_id, variant_id`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+	for itemRows.Next() {
+		var returnID int64
+		var item models.ReturnItem
+		if err := itemRows.Scan(&returnID, &item.ProductID, &item.VariantID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		i := index[returnID]
+		returns[i].Items = append(returns[i].Items, item)
+	}
+	return returns, itemRows.Err()
+}
+
+// restockReturnItems возвращает на склад товары заявки. Порядок блокировок тот же, что в restockOrderItems
+func restockReturnItems(ctx context.Context, tx *sqlx.Tx, returnID int64) error {
+	_, err := tx.ExecContext(ctx, `SELECT p.product_id FROM products p
+		JOIN order_return_items ri ON ri.product_id = p.product_id
+		WHERE ri.return_id = $1
+		ORDER BY p.product_id
+		FOR UPDATE OF p`, returnID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT v.variant_id FROM product_variants v
+		JOIN order_return_items ri ON ri.variant_id = v.variant_id
+		WHERE ri.return_id = $1
+		ORDER BY v.product_id, v.variant_id
+		FOR UPDATE OF v`, returnID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE products p SET stock = p.stock + ri.quantity
+		FROM order_return_items ri
+		WHERE ri.return_id = $1 AND p.product_id = ri.product_id AND ri.variant_id IS NULL`, returnID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE product_variants v SET stock = v.stock + ri.quantity
+		FROM order_return_items ri
+		WHERE ri.return_id = $1 AND v.variant_id = ri.variant_id`, returnID)
+	return err
+}
+
+func mergeReturnItems(items []models.ReturnItem) []models.ReturnItem {
+	orderItems := make([]models.OrderItem, 0, len(items))
+	for _, item := range items {
+		orderItems = append(orderItems, models.OrderItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+	merged := mergeOrderItems(orderItems)
+	result := make([]models.ReturnItem, 0, len(merged))
+	for _, item := range merged {
+		result = append(result, models.ReturnItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+	return result
+}
+
+// CreateRefund резервирует сумму возврата до обращения к провайдеру и записывает его в статусе processing: заказ блокируется,
+// и сумма всех неотклоненных возвратов не может превысить сумму заказа.
+// По одной заявке возможен только один неотклоненный возврат не больше суммы заявки
+func (s *StorageProducts) CreateRefund(ctx context.Context, refund models.Refund) (*models.Refund, error) {
+	const op = "storages.shopstorage.CreateRefund"
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var status models.OrderStatus
+	var sum models.Money
+	err = tx.QueryRowContext(ctx, `SELECT status, sum, currency FROM orders WHERE order_id = $1 FOR UPDATE`, refund.OrderID).Scan(&status, &sum, &sum.Currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	//Отмененный заказ тоже можно вернуть, если он был оплачен
+	if !status.Returnable() {
+		var paid bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM payments WHERE order_id = $1 AND status IN ($2, $3))`,
+			refund.OrderID, models.PaymentStatusSucceeded, models.PaymentStatusRefunded).Scan(&paid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if status != models.OrderStatusCanceled || !paid {
+			return nil, models.ErrOrderNotReturnable
+		}
+	}
+	if refund.Amount.Currency != sum.Currency {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrCurrencyMismatch)
+	}
+
+	refunded := models.Money{Currency: sum.Currency}
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE order_id = $1 AND status <> $2`,
+		refund.OrderID, models.RefundStatusFailed).Scan(&refunded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if refunded.Amount+refund.Amount.Amount > sum.Amount {
+		return nil, models.ErrRefundExceedsAmount
+	}
+
+	var returnID sql.NullInt64
+	if refund.ReturnID != 0 {
+		var returnStatus models.ReturnStatus
+		returnAmount := models.Money{Currency: sum.Currency}
+		err = tx.QueryRowContext(ctx, `SELECT status, amount FROM order_returns WHERE id = $1 AND order_id = $2 FOR UPDATE`,
+			refund.ReturnID, refund.OrderID).Scan(&returnStatus, &returnAmount)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrReturnNotFound
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if returnStatus != models.ReturnStatusApproved {
+			return nil, models.ErrReturnNotApproved
+		}
+		//Заявка закрывается только после ответа провайдера, поэтому второй возврат по ней
+		//отсекаем здесь, пока заявка заблокирована: иначе параллельный вызов вернул бы деньги дважды
+		var inProgress bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM refunds WHERE return_id = $1 AND status <> $2)`,
+			refund.ReturnID, models.RefundStatusFailed).Scan(&inProgress)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if inProgress {
+			return nil, models.ErrReturnRefunding
+		}
+		if refund.Amount.Amount > returnAmount.Amount {
+			return nil, models.ErrRefundExceedsReturn
+		}
+		returnID = sql.NullInt64{Int64: refund.ReturnID, Valid: true}
+	}
+
+	refund.Status = models.RefundStatusProcessing
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO refunds (order_id, amount, status, reason, return_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		refund.OrderID, refund.Amount, refund.Status, refund.Reason, returnID,
+	).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &refund, nil
+}
+
+// ClaimRefund забирает на выплату самый старый requested-возврат заказа, переводя его в processing.
+// Параллельный вызов не получит тот же возврат. Если ждущих возвратов нет, возвращает ErrRefundNotFound
+func (s *StorageProducts) ClaimRefund(ctx context.Context, orderID int64) (*models.Refund, error) {
+	const op = "storages.shopstorage.ClaimRefund"
+	const query = `UPDATE refunds SET status = $3
+		WHERE id = (
+			SELECT id FROM refunds WHERE order_id = $1 AND status = $2
+			ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING id, order_id, amount, status, reason, COALESCE(return_id, 0) AS return_id, external_id, created_at`
+
+	var refund models.Refund
+	err := s.db.GetContext(ctx, &refund, query, orderID, models.RefundStatusRequested, models.RefundStatusProcessing)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	err = s.db.QueryRowContext(ctx, `SELECT currency FROM orders WHERE order_id = $1`, orderID).Scan(&refund.Amount.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &refund, nil
+}
+
+// CompleteRefund записывает ответ провайдера. Успешный возврат по заявке закрывает заявку
+func (s *StorageProducts) CompleteRefund(ctx context.Context, refund models.Refund) error {
+	const op = "storages.shopstorage.CompleteRefund"
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE refunds SET status = $2, external_id = $3 WHERE id = $1`,
+		refund.ID, refund.Status, refund.ExternalID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if refund.Status == models.RefundStatusSucceeded && refund.ReturnID != 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE order_returns SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+			refund.ReturnID, models.ReturnStatusRefunded)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// loadOrderRefunds подгружает возвраты денег для списка заказов одним запросом
+func (s *StorageProducts) loadOrderRefunds(ctx context.Context, orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(orders))
+	index := make(map[int64]int, len(orders))
+	for i, order := range orders {
+		ids = append(ids, order.ID)
+		index[order.ID] = i
+	}
+
+	var refunds []models.Refund
+	err := s.db.SelectContext(ctx, &refunds, `SELECT id, order_id, amount, status, reason, COALESCE(return_id, 0) AS return_id, external_id, created_at
+		FROM refunds WHERE order_id = ANY($1) ORDER BY id`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	for _, refund := range refunds {
+		i := index[refund.OrderID]
+		//Возврат всегда в валюте заказа
+		refund.Amount.Currency = orders[i].Sum.Currency
+		orders[i].Refunds = append(orders[i].Refunds, refund)
+	}
+	return nil
+}
+
+// outboxLockKey - ключ advisory-блокировки relay: события публикует одна реплика за раз,
+// иначе две реплики могли бы отправить события одного заказа не по порядку
+const outboxLockKey = 7_340_001
+
+// insertOutboxEvent пишет событие заказа в outbox внутри транзакции, которая меняет заказ
+func insertOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventType string, event models.OrderEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload) VALUES ($1, $2, $3, $4)`,
+		models.AggregateOrder, event.OrderID, eventType, string(payload))
+	return err
+}
+
+// orderEventItems - строки заказа для событий склада
+func orderEventItems(ctx context.Context, tx *sqlx.Tx, orderID int64) ([]models.EventItem, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT product_id, COALESCE(variant_id, 0), quantity
+		FROM order_items WHERE order_id = $1 ORDER BY order_item_id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.EventItem
+	for rows.Next() {
+		var item models.EventItem
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// PublishPendingEvents передает неопубликованные события в publish по порядку id и отмечает результат.
+// Все происходит в одной транзакции под advisory-блокировкой; если ее держит другая реплика, возвращает 0.
+// Событие, для которого publish вернул ErrEventDeferred, остается в очереди без увеличения attempts.
+// publish должен ограничивать время сетевых вызовов: транзакция и блокировка держатся, пока он работает
+func (s *StorageProducts) PublishPendingEvents(ctx context.Context, limit int, publish func(ctx context.Context, event models.Event) error) (int, error) {
+	const op = "storages.shopstorage.PublishPendingEvents"
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockKey).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, aggregate_type, aggregate_id, event_type, payload, attempts, created_at
+		FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		if err := rows.Scan(&event.ID, &event.AggregateType, &event.AggregateID, &event.Type, &event.Payload, &event.Attempts, &event.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	published := 0
+	for _, event := range events {
+		err := publish(ctx, event)
+		switch {
+		case err == nil:
+			_, err = tx.ExecContext(ctx, `UPDATE outbox SET published_at = CURRENT_TIMESTAMP, attempts = attempts + 1 WHERE id = $1`, event.ID)
+			published++
+		case errors.Is(err, models.ErrEventDeferred):
+			continue
+		default:
+			_, err = tx.ExecContext(ctx, `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`, event.ID, err.Error())
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	//Если коммит не пройдет, события уйдут повторно: это и есть at-least-once
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return published, nil
 }